@@ -0,0 +1,77 @@
+package radix
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrEmptyKey is returned when an operation is given an empty key.
+var ErrEmptyKey = errors.New("radix: empty key")
+
+// OpKind is the kind of an Op.
+type OpKind int
+
+const (
+	OpInsert OpKind = iota // insert or overwrite the key
+	OpCreate               // insert the key, fail if it is already present
+	OpRemove               // remove the key
+)
+
+// Op is a single operation in a batch, see Apply.
+type Op struct {
+	Kind  OpKind
+	Key   string
+	Value interface{}
+}
+
+// Apply applies the operations in batch in order. If any of them fails, because
+// the key is empty, an OpCreate finds the key already present or an insert exceeds the
+// limits of the tree, the operations applied so far are reverted and an error
+// is returned: either all operations are applied or none.
+// r must be the root of the radix tree.
+func (r *Radix) Apply(batch []Op) error {
+	t := r.tree
+	if t != nil {
+		t.replaying = true // changes are added to the history once all succeed
+		t.batching = true
+	}
+	done := make([]change, 0, len(batch))
+	for i, op := range batch {
+		var old interface{}
+		if n, exact := r.find(op.Key); exact {
+			old = n.Value
+		}
+		var err error
+		switch {
+		case op.Key == "":
+			err = ErrEmptyKey
+		case op.Kind == OpCreate && old != nil:
+			err = wrap(ErrExists, op.Key)
+		case op.Kind == OpRemove:
+			r.Remove(op.Key)
+		default:
+			_, err = r.TryInsert(op.Key, op.Value)
+		}
+		if err != nil {
+			for j := len(done) - 1; j >= 0; j-- {
+				r.replay(done[j].key, done[j].old)
+			}
+			if t != nil {
+				t.replaying, t.batching = false, false
+			}
+			return wrap(err, "op "+strconv.Itoa(i))
+		}
+		c := change{key: op.Key, old: old}
+		if op.Kind != OpRemove {
+			c.new = op.Value
+		}
+		done = append(done, c)
+	}
+	if t != nil {
+		t.replaying, t.batching = false, false
+		for _, c := range done {
+			t.record(c.key, c.old, c.new)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package radix
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	r := NewWithLimits(Limits{MaxKeys: 4})
+	r.KeepHistory(10)
+	r.Insert("test", "a")
+	r.Insert("team", "b")
+
+	err := r.Apply([]Op{
+		{Kind: OpInsert, Key: "tester", Value: "c"},
+		{Kind: OpRemove, Key: "test"},
+		{Kind: OpCreate, Key: "team", Value: "d"},
+	})
+	if !errors.Is(err, ErrExists) {
+		t.Fatalf("Apply should fail with ErrExists, got %v", err)
+	}
+	if _, exact := r.Find("tester"); exact {
+		t.Logf("tester should not be inserted by a failed Apply")
+		t.Fail()
+	}
+	if n, exact := r.Find("test"); !exact || n.Value != "a" {
+		t.Logf("test should not be removed by a failed Apply")
+		t.Fail()
+	}
+
+	err = r.Apply([]Op{
+		{Kind: OpInsert, Key: "tester", Value: "c"},
+		{Kind: OpInsert, Key: "x", Value: "x"},
+		{Kind: OpInsert, Key: "y", Value: "y"},
+	})
+	if !errors.Is(err, ErrLimit) || r.Len() != 2 {
+		t.Logf("Apply should fail with ErrLimit and leave 2 keys, got %v and %d", err, r.Len())
+		t.Fail()
+	}
+
+	err = r.Apply([]Op{
+		{Kind: OpCreate, Key: "tester", Value: "c"},
+		{Kind: OpRemove, Key: "team"},
+	})
+	if err != nil || r.Len() != 2 {
+		t.Fatalf("Apply should succeed: %v", err)
+	}
+	if r.Undo(2) != 2 || r.Len() != 2 {
+		t.Logf("a successful Apply should be recorded in the history")
+		t.Fail()
+	}
+	if _, exact := r.Find("team"); !exact {
+		t.Logf("team should be back after Undo")
+		t.Fail()
+	}
+}
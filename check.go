@@ -0,0 +1,122 @@
+//go:build !tinygo
+
+package radix
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Checked is a debugging aid: it wraps a tree and mirrors every Insert and Remove into a
+// plain map. After each operation the results of the tree are compared with the map and the
+// first difference is reported by Err, together with the operations that led up to it.
+// All operations are slow, Checked is meant for tests and fuzzing.
+type Checked struct {
+	tree   *Radix
+	shadow map[string]interface{}
+	ops    []string
+	err    error
+}
+
+// NewChecked returns a Checked wrapping a new, empty tree.
+func NewChecked() *Checked {
+	return &Checked{tree: New(), shadow: make(map[string]interface{})}
+}
+
+// Tree returns the wrapped tree. Changes made to it directly are not mirrored.
+func (c *Checked) Tree() *Radix { return c.tree }
+
+// Err returns the first difference found between the tree and the map, or nil.
+func (c *Checked) Err() error { return c.err }
+
+// Insert inserts key into the tree and the map, see Radix.Insert.
+func (c *Checked) Insert(key string, value interface{}) *Radix {
+	c.ops = append(c.ops, fmt.Sprintf("Insert(%q)", key))
+	n := c.tree.Insert(key, value)
+	if value == nil {
+		delete(c.shadow, key)
+	} else {
+		c.shadow[key] = value
+	}
+	if n == nil || n.Key() != key {
+		c.fail("Insert returned the wrong node for %q", key)
+	}
+	c.check(key)
+	return n
+}
+
+// Remove removes key from the tree and the map, see Radix.Remove.
+func (c *Checked) Remove(key string) *Radix {
+	c.ops = append(c.ops, fmt.Sprintf("Remove(%q)", key))
+	_, present := c.shadow[key]
+	n := c.tree.Remove(key)
+	delete(c.shadow, key)
+	if present && n == nil {
+		c.fail("Remove returned nil for present key %q", key)
+	}
+	c.check(key)
+	return n
+}
+
+// Find looks key up in the tree and checks the result against the map, see Radix.Find.
+func (c *Checked) Find(key string) (node *Radix, exact bool) {
+	node, exact = c.tree.Find(key)
+	c.check(key)
+	return node, exact
+}
+
+// Verify compares all keys and values in the tree with the map.
+func (c *Checked) Verify() error {
+	keys := make([]string, 0, len(c.shadow))
+	for k := range c.shadow {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	i := 0
+	c.tree.ordered("", func(key string, n *Radix) bool {
+		if i >= len(keys) || keys[i] != key {
+			c.fail("tree holds unexpected key %q", key)
+			return false
+		}
+		if want := c.shadow[key]; !reflect.DeepEqual(n.Uncompressed(), want) {
+			c.fail("tree holds %v for %q, want %v", n.Uncompressed(), key, want)
+			return false
+		}
+		i++
+		return true
+	})
+	if c.err == nil && i != len(keys) {
+		c.fail("tree is missing key %q", keys[i])
+	}
+	return c.err
+}
+
+// check compares the tree with the map for key.
+func (c *Checked) check(key string) {
+	if c.err != nil {
+		return
+	}
+	want, present := c.shadow[key]
+	n, exact := c.tree.find(key)
+	switch {
+	case present && !exact:
+		c.fail("Find(%q) does not find the key", key)
+	case present && !reflect.DeepEqual(n.Uncompressed(), want):
+		c.fail("Find(%q) returns %v, want %v", key, n.Uncompressed(), want)
+	case !present && exact:
+		c.fail("Find(%q) finds a key that is not present", key)
+	case present && !c.tree.HasPrefix(key):
+		c.fail("HasPrefix(%q) is false for a present key", key)
+	case c.tree.Len() != len(c.shadow):
+		c.fail("Len is %d, want %d", c.tree.Len(), len(c.shadow))
+	}
+}
+
+func (c *Checked) fail(format string, a ...interface{}) {
+	if c.err != nil {
+		return
+	}
+	c.err = fmt.Errorf("radix: %s, after %s", fmt.Sprintf(format, a...), strings.Join(c.ops, ", "))
+}
@@ -0,0 +1,60 @@
+//go:build !tinygo
+
+package radix
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestChecked(t *testing.T) {
+	c := NewChecked()
+	rnd := rand.New(rand.NewSource(1))
+	letters := "abc"
+	for i := 0; i < 2000; i++ {
+		b := make([]byte, 1+rnd.Intn(5))
+		for j := range b {
+			b[j] = letters[rnd.Intn(len(letters))]
+		}
+		key := string(b)
+		switch rnd.Intn(3) {
+		case 0, 1:
+			c.Insert(key, i)
+		case 2:
+			c.Remove(key)
+		}
+		c.Find(key[:len(key)/2+1])
+		if c.Err() != nil {
+			t.Fatal(c.Err())
+		}
+	}
+	if err := c.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Modify the tree behind the back of the map.
+	c.Tree().Insert("ccccccc", 1)
+	err := c.Verify()
+	if err == nil || !strings.Contains(err.Error(), "ccccccc") {
+		t.Logf("Verify should report ccccccc, got %v", err)
+		t.Fail()
+	}
+}
+
+func TestCheckedValues(t *testing.T) {
+	c := NewChecked()
+	c.Insert("a", []int{1})
+	c.Insert("b", []int{2})
+	if err := c.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	// Change a value behind the back of the map.
+	n, _ := c.Tree().Find("b")
+	n.Value = []int{3}
+	err := c.Verify()
+	if err == nil || !strings.Contains(err.Error(), `"b"`) {
+		t.Logf("Verify should report the value of b, got %v", err)
+		t.Fail()
+	}
+}
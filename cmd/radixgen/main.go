@@ -0,0 +1,107 @@
+// Radixgen generates a Go source file holding a read-only table built from a
+// list of keys and values, so lookup tables can be compiled into a binary.
+//
+// The input has one entry per line: the key, a tab and the value. If there is
+// no tab the value is the empty string. Empty lines are ignored.
+//
+// Use it with go:generate:
+//
+//	//go:generate radixgen -pkg mime -var Types -o types.go types.txt
+//
+// where types.txt maps file extensions to media types, one ".html\ttext/html"
+// per line. The generated file defines a package level *radix.Frozen variable
+// with string values, so Types.Find(".html") returns "text/html". The table is
+// a single string constant in the layout written by WriteFrozen, which
+// radix.OpenFrozen uses in place: nothing is inserted or copied when the
+// package is initialized. The table holds binary data, such as NUL bytes, that
+// a raw string literal can not hold, so the constant is an interpreted string
+// literal.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/miekg/radix"
+)
+
+var (
+	pkg  = flag.String("pkg", "main", "package name of the generated file")
+	name = flag.String("var", "Tree", "name of the generated variable")
+	out  = flag.String("o", "", "output file, default is standard output")
+)
+
+func main() {
+	flag.Parse()
+	in := io.Reader(os.Stdin)
+	if flag.NArg() > 0 {
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		in = f
+	}
+	var buf bytes.Buffer
+	if err := generate(&buf, *pkg, *name, in); err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		os.Stdout.Write(buf.Bytes())
+		return
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate reads the entries from in and writes the formatted Go source to w.
+func generate(w io.Writer, pkg, name string, in io.Reader) error {
+	r := radix.New()
+	scanner := bufio.NewScanner(in)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if text == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(text, "\t")
+		if key == "" {
+			return fmt.Errorf("radixgen: line %d: empty key", line)
+		}
+		r.Insert(key, value)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	var table bytes.Buffer
+	if err := r.WriteFrozen(&table, radix.StringCodec{}); err != nil {
+		return err
+	}
+
+	first, size := utf8.DecodeRuneInString(name)
+	data := string(unicode.ToLower(first)) + name[size:] + "Data"
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by radixgen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import \"github.com/miekg/radix\"\n\n")
+	fmt.Fprintf(&b, "var %s = func() *radix.Frozen {\n", name)
+	fmt.Fprintf(&b, "\tf, err := radix.OpenFrozen(%s)\n\tif err != nil {\n\t\tpanic(err)\n\t}\n\treturn f\n}()\n\n", data)
+	fmt.Fprintf(&b, "const %s = %s\n", data, strconv.Quote(table.String()))
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
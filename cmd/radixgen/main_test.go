@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/miekg/radix"
+)
+
+func TestGenerate(t *testing.T) {
+	in := strings.NewReader(".html\ttext/html\n\n.png\timage/png\n.txt\n")
+	var out bytes.Buffer
+	if err := generate(&out, "mime", "Types", in); err != nil {
+		t.Fatal(err)
+	}
+	src := out.String()
+	for _, want := range []string{
+		"package mime\n",
+		"var Types = func() *radix.Frozen {",
+		"radix.OpenFrozen(typesData)",
+		"const typesData = ",
+	} {
+		if !strings.Contains(src, want) {
+			t.Logf("generated source should contain %q:\n%s", want, src)
+			t.Fail()
+		}
+	}
+
+	// Open the table in the generated constant.
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var data string
+	ast.Inspect(f, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.ValueSpec); ok && spec.Names[0].Name == "typesData" {
+			lit, ok := spec.Values[0].(*ast.BasicLit)
+			if !ok {
+				t.Fatalf("typesData should be a single string literal, is %T", spec.Values[0])
+			}
+			data, _ = strconv.Unquote(lit.Value)
+		}
+		return true
+	})
+	table, err := radix.OpenFrozen(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{".html": "text/html", ".png": "image/png", ".txt": ""} {
+		if v, ok := table.Find(key); !ok || v != want {
+			t.Logf("%s should be found with value %q, found %q", key, want, v)
+			t.Fail()
+		}
+	}
+
+	if err := generate(&out, "mime", "Types", strings.NewReader("\tvalue\n")); err == nil {
+		t.Logf("an empty key should be an error")
+		t.Fail()
+	}
+}
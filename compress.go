@@ -0,0 +1,100 @@
+package radix
+
+// Compressor compresses values, see CompressValues.
+type Compressor interface {
+	Compress(b []byte) ([]byte, error)
+	Decompress(b []byte) ([]byte, error)
+}
+
+// Compressed is the Value of a node holding a value compressed by CompressValues.
+type Compressed struct {
+	data []byte
+	raw  int  // length of the uncompressed value
+	str  bool // the value is a string, not a []byte
+	c    Compressor
+}
+
+// Value returns the uncompressed value, a string or a []byte like the value that was inserted.
+func (v *Compressed) Value() (interface{}, error) {
+	b, err := v.c.Decompress(v.data)
+	if err != nil {
+		return nil, err
+	}
+	if v.str {
+		return string(b), nil
+	}
+	return b, nil
+}
+
+// plain returns value uncompressed if it is a *Compressed, otherwise value itself.
+func plain(value interface{}) (interface{}, error) {
+	if c, ok := value.(*Compressed); ok {
+		return c.Value()
+	}
+	return value, nil
+}
+
+// Uncompressed returns the Value of r, uncompressed if it was compressed by CompressValues, or
+// nil if it can not be decompressed. Nodes returned by Find, Prefix and the other lookups hold
+// the compressed value in Value.
+func (r *Radix) Uncompressed() interface{} {
+	v, _ := plain(r.Value)
+	return v
+}
+
+// CompressValues makes Insert compress string and []byte values longer than threshold bytes
+// with c. A compressed value is stored as a *Compressed in the Value of its node, see
+// Uncompressed. Every function that passes values, such as Get, Walk, Fold, SetUnder, the
+// Cursor and the snapshot writers, passes them uncompressed, and every function that stores
+// values compresses them. Values that do not get smaller are stored as is. A nil c stops compressing new values, values
+// already compressed stay compressed. r must be the root of the radix tree.
+func (r *Radix) CompressValues(threshold int, c Compressor) {
+	t := r.settings()
+	t.compressor, t.threshold = c, threshold
+}
+
+// CompressionStats returns the size of the compressed values in r before and after compression.
+// r must be the root of the radix tree.
+func (r *Radix) CompressionStats() (raw, compressed int64) {
+	if r.tree == nil {
+		return 0, 0
+	}
+	return r.tree.raw, r.tree.compressed
+}
+
+// compress returns value compressed, if values are compressed and value is large enough.
+func (t *tree) compress(value interface{}) interface{} {
+	if t.compressor == nil {
+		return value
+	}
+	var b []byte
+	str := false
+	switch x := value.(type) {
+	case string:
+		b, str = []byte(x), true
+	case []byte:
+		b = x
+	default:
+		return value
+	}
+	if len(b) <= t.threshold {
+		return value
+	}
+	data, err := t.compressor.Compress(b)
+	if err != nil || len(data) >= len(b) {
+		return value
+	}
+	return &Compressed{data: data, raw: len(b), str: str, c: t.compressor}
+}
+
+// account updates the compression stats when old is replaced by new.
+func (t *tree) account(old, new interface{}) {
+	if v, ok := old.(*Compressed); ok {
+		t.raw -= int64(v.raw)
+		t.compressed -= int64(len(v.data))
+	}
+	if v, ok := new.(*Compressed); ok {
+		t.raw += int64(v.raw)
+		t.compressed += int64(len(v.data))
+	}
+}
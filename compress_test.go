@@ -0,0 +1,152 @@
+//go:build !tinygo
+
+package radix
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompressValues(t *testing.T) {
+	r := New()
+	r.CompressValues(64, FlateCompressor{})
+	blob := strings.Repeat(`{"name": "value"}`, 100)
+	r.Insert("small", "tiny")
+	r.Insert("blob", blob)
+	r.Insert("bytes", []byte(blob))
+
+	if n, _ := r.Find("small"); n.Value != "tiny" {
+		t.Logf("values under the threshold should not be compressed")
+		t.Fail()
+	}
+	if n, _ := r.Find("blob"); n.Value == blob {
+		t.Logf("values over the threshold should be compressed")
+		t.Fail()
+	}
+	if v := r.Get("blob"); v != blob {
+		t.Logf("Get should return the uncompressed string")
+		t.Fail()
+	}
+	if v, ok := r.Get("bytes").([]byte); !ok || !bytes.Equal(v, []byte(blob)) {
+		t.Logf("Get should return the uncompressed []byte")
+		t.Fail()
+	}
+	raw, compressed := r.CompressionStats()
+	if raw != int64(2*len(blob)) || compressed == 0 || compressed >= raw {
+		t.Logf("stats should show %d raw bytes compressed to less, show %d and %d", 2*len(blob), raw, compressed)
+		t.Fail()
+	}
+	r.Remove("blob")
+	r.Insert("bytes", "short")
+	if raw, compressed := r.CompressionStats(); raw != 0 || compressed != 0 {
+		t.Logf("stats should be 0 after removing the compressed values, are %d and %d", raw, compressed)
+		t.Fail()
+	}
+}
+
+func TestCompressValuesExport(t *testing.T) {
+	r := New()
+	r.CompressValues(64, FlateCompressor{})
+	blob := strings.Repeat(`{"name": "value"}`, 100)
+	r.Insert("blob", blob)
+
+	var buf bytes.Buffer
+	if err := r.WriteSnapshot(&buf, StringCodec{}, false); err != nil {
+		t.Fatal(err)
+	}
+	s, err := ReadSnapshot(&buf, StringCodec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, exact := s.Find("blob"); !exact || n.Value != blob {
+		t.Logf("the snapshot should hold the uncompressed value")
+		t.Fail()
+	}
+
+	if b, err := r.MarshalNestedJSON("/"); err != nil || !strings.Contains(string(b), "value") {
+		t.Logf("MarshalNestedJSON should encode the uncompressed value, got %s, %v", b, err)
+		t.Fail()
+	}
+
+	w := httptest.NewRecorder()
+	r.Handler(StringCodec{}).ServeHTTP(w, httptest.NewRequest("GET", "/blob", nil))
+	if b, _ := io.ReadAll(w.Result().Body); string(b) != blob {
+		t.Logf("the handler should return the uncompressed value, got %q", b)
+		t.Fail()
+	}
+}
+
+func TestCompressValuesAccess(t *testing.T) {
+	r := New()
+	r.CompressValues(64, FlateCompressor{})
+	blob := strings.Repeat(`{"name": "value"}`, 100)
+	r.Insert("a/blob", blob)
+	r.Insert("a/small", "tiny")
+
+	r.Walk(func(key string, v interface{}) error {
+		if _, ok := v.(string); !ok {
+			t.Logf("Walk should pass %s uncompressed, got %T", key, v)
+			t.Fail()
+		}
+		return nil
+	})
+	if v := r.Fold("", func(acc interface{}, _ string, v interface{}) interface{} { return acc.(string) + v.(string) }); v != blob+"tiny" {
+		t.Logf("Fold should pass uncompressed values")
+		t.Fail()
+	}
+	c := r.Cursor()
+	if c.First(); c.Value() != blob {
+		t.Logf("the cursor should return the uncompressed value")
+		t.Fail()
+	}
+	if n, _ := r.Find("a/blob"); n.Uncompressed() != blob {
+		t.Logf("Uncompressed should return the uncompressed value")
+		t.Fail()
+	}
+
+	longer := strings.Repeat(`{"name": "other"}`, 200)
+	r.SetUnder("a/", func(key string, old interface{}) interface{} {
+		if key != "a/blob" {
+			return nil
+		}
+		if old.(string) != blob {
+			t.Logf("SetUnder should pass the uncompressed value")
+			t.Fail()
+		}
+		return longer
+	})
+	if n, _ := r.Find("a/blob"); n.Value == longer {
+		t.Logf("SetUnder should compress the new value")
+		t.Fail()
+	}
+	if r.Get("a/blob") != longer {
+		t.Logf("Get should return the value set by SetUnder")
+		t.Fail()
+	}
+	if raw, _ := r.CompressionStats(); raw != int64(len(longer)) {
+		t.Logf("stats should count the %d bytes set by SetUnder, count %d", len(longer), raw)
+		t.Fail()
+	}
+}
+
+func TestOnChangeUncompressed(t *testing.T) {
+	r := New()
+	r.CompressValues(16, FlateCompressor{})
+	var recs []Record
+	r.OnChange(func(rec Record) { recs = append(recs, rec) })
+	long := strings.Repeat("long value ", 100)
+	r.Insert("a", long)
+	r.SetUnder("", func(_ string, old interface{}) interface{} { return old.(string) + "!" })
+	if len(recs) != 2 || recs[0].Op.Value != long || recs[1].Op.Value != long+"!" {
+		t.Logf("records should hold the uncompressed values, hold %v", recs)
+		t.Fail()
+	}
+	n, _ := r.Find("a")
+	if _, ok := n.Value.(*Compressed); !ok {
+		t.Logf("value of a should be stored compressed")
+		t.Fail()
+	}
+}
@@ -0,0 +1,198 @@
+package radix
+
+import (
+	"sort"
+)
+
+// Cursor walks the non-nil Value nodes of a radix tree in lexical order.
+// Unlike Next and Prev a cursor does not wrap around: when it moves past
+// the first or last node it becomes invalid.
+type Cursor struct {
+	root *Radix
+	node *Radix
+}
+
+// Cursor returns a new cursor for r. The cursor is not positioned, use
+// Seek, First or Last to position it. r must be the root of the radix tree.
+func (r *Radix) Cursor() *Cursor {
+	return &Cursor{root: r}
+}
+
+// Valid returns true if the cursor is positioned on a node.
+func (c *Cursor) Valid() bool { return c.node != nil }
+
+// Node returns the node the cursor is positioned on, or nil.
+func (c *Cursor) Node() *Radix { return c.node }
+
+// Key returns the full key of the current node, or the empty string if the
+// cursor isn't valid.
+func (c *Cursor) Key() string {
+	if c.node == nil {
+		return ""
+	}
+	return c.node.Key()
+}
+
+// Value returns the Value of the current node, or nil if the cursor isn't valid.
+func (c *Cursor) Value() interface{} {
+	if c.node == nil {
+		return nil
+	}
+	return c.node.Uncompressed()
+}
+
+// First positions the cursor on the smallest key. It returns false if the tree is empty.
+func (c *Cursor) First() bool {
+	c.node = first(c.root)
+	return c.node != nil
+}
+
+// Last positions the cursor on the largest key. It returns false if the tree is empty.
+func (c *Cursor) Last() bool {
+	c.node = last(c.root)
+	return c.node != nil
+}
+
+// Seek positions the cursor on the smallest key that is larger than or equal to key.
+// It returns false if there is no such key.
+func (c *Cursor) Seek(key string) bool {
+	c.node = c.root.seek(key)
+	return c.node != nil
+}
+
+// Next moves the cursor to the next key. It returns false if there is none, the
+// cursor is then invalid.
+func (c *Cursor) Next() bool {
+	if c.node == nil {
+		return false
+	}
+	c.node = c.root.successor(c.node)
+	return c.node != nil
+}
+
+// Prev moves the cursor to the previous key. It returns false if there is none, the
+// cursor is then invalid.
+func (c *Cursor) Prev() bool {
+	if c.node == nil {
+		return false
+	}
+	c.node = c.root.predecessor(c.node)
+	return c.node != nil
+}
+
+// sortedChildren returns the first bytes of the children of m in ascending order.
+func sortedChildren(m map[byte]*Radix) []byte {
+	keys := make([]byte, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// first returns the smallest node with a non-nil Value in the subtree r, or nil.
+func first(r *Radix) *Radix {
+	if r.Value != nil {
+		return r
+	}
+	for _, k := range sortedChildren(r.children) {
+		if f := first(r.children[k]); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+// last returns the largest node with a non-nil Value in the subtree r, or nil.
+func last(r *Radix) *Radix {
+	keys := sortedChildren(r.children)
+	for i := len(keys) - 1; i >= 0; i-- {
+		if l := last(r.children[keys[i]]); l != nil {
+			return l
+		}
+	}
+	if r.Value != nil {
+		return r
+	}
+	return nil
+}
+
+// after returns the smallest node with a non-nil Value that comes after the
+// whole subtree n. The search does not go above root.
+func (root *Radix) after(n *Radix) *Radix {
+	for n != root && n.parent != nil {
+		siblings := n.parent.children
+		for k, found := smallestSuccessor(siblings, n.key[0]); found; k, found = smallestSuccessor(siblings, k) {
+			if f := first(siblings[k]); f != nil {
+				return f
+			}
+		}
+		n = n.parent
+	}
+	return nil
+}
+
+// successor returns the node with a non-nil Value following n in lexical order.
+func (root *Radix) successor(n *Radix) *Radix {
+	for _, k := range sortedChildren(n.children) {
+		if f := first(n.children[k]); f != nil {
+			return f
+		}
+	}
+	return root.after(n)
+}
+
+// predecessor returns the node with a non-nil Value preceding n in lexical order.
+func (root *Radix) predecessor(n *Radix) *Radix {
+	for n != root && n.parent != nil {
+		siblings := n.parent.children
+		for k, found := largestPredecessor(siblings, n.key[0]); found; k, found = largestPredecessor(siblings, k) {
+			if l := last(siblings[k]); l != nil {
+				return l
+			}
+		}
+		n = n.parent
+		if n.Value != nil {
+			return n
+		}
+	}
+	return nil
+}
+
+// seek returns the smallest node with a non-nil Value whose key, relative to r,
+// is larger than or equal to key.
+func (r *Radix) seek(key string) *Radix {
+	n := r
+	for {
+		if key == "" {
+			if f := first(n); f != nil {
+				return f
+			}
+			return r.after(n)
+		}
+		child, ok := n.children[key[0]]
+		if !ok {
+			for k, found := smallestSuccessor(n.children, key[0]); found; k, found = smallestSuccessor(n.children, k) {
+				if f := first(n.children[k]); f != nil {
+					return f
+				}
+			}
+			return r.after(n)
+		}
+		_, i := longestCommonPrefix(key, child.key)
+		switch {
+		case i == len(child.key):
+			key = key[i:]
+			n = child
+		case i == len(key) || key[i] < child.key[i]:
+			// everything in child is larger than key
+			if f := first(child); f != nil {
+				return f
+			}
+			return r.after(child)
+		default:
+			// everything in child is smaller than key
+			return r.after(child)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package radix
+
+import (
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	r := New()
+	keys := []string{"nl.miek", "nl.miek.a", "nl.miek.c", "nl.miek.c.a", "nl.miek.c.c", "nl.miek.d"}
+	for _, k := range keys {
+		r.Insert(k, k)
+	}
+	c := r.Cursor()
+	i := 0
+	for ok := c.First(); ok; ok = c.Next() {
+		if c.Key() != keys[i] || c.Value() != keys[i] {
+			t.Logf("Next: key %d should be %s, is %s", i, keys[i], c.Key())
+			t.Fail()
+		}
+		i++
+	}
+	if i != len(keys) {
+		t.Logf("Cursor should visit %d keys, visited %d", len(keys), i)
+		t.Fail()
+	}
+	i = len(keys) - 1
+	for ok := c.Last(); ok; ok = c.Prev() {
+		if c.Key() != keys[i] {
+			t.Logf("Prev: key %d should be %s, is %s", i, keys[i], c.Key())
+			t.Fail()
+		}
+		i--
+	}
+	seek := map[string]string{
+		"":            "nl.miek",
+		"nl.miek":     "nl.miek",
+		"nl.miek.b":   "nl.miek.c",
+		"nl.miek.c.b": "nl.miek.c.c",
+		"nl.miek.c.d": "nl.miek.d",
+		"nl.mie":      "nl.miek",
+		"a":           "nl.miek",
+		"nl.miek.e":   "",
+		"z":           "",
+	}
+	for k, want := range seek {
+		c.Seek(k)
+		if c.Key() != want {
+			t.Logf("Seek(%s) should be %s, is %s", k, want, c.Key())
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package radix
+
+// DAWG is a read-only set of keys stored as a minimized directed acyclic word graph: besides
+// sharing prefixes, as a radix tree does, identical suffixes are shared too. This makes it
+// much smaller for sets such as the inflected forms of words. A DAWG only holds keys, not values.
+type DAWG struct {
+	root  *dstate
+	len   int
+	count int // number of states
+}
+
+type dstate struct {
+	final bool
+	edges []dedge // sorted on b
+	id    int
+}
+
+type dedge struct {
+	b  byte
+	to *dstate
+}
+
+// DAWG returns the keys of r as a minimized DAWG. Keys are relative to r.
+func (r *Radix) DAWG() *DAWG {
+	b := &dawgBuilder{d: &DAWG{root: &dstate{}}, register: make(map[string]*dstate)}
+	r.ordered("", func(key string, _ *Radix) bool {
+		b.add(key)
+		return true
+	})
+	b.minimize(0)
+	b.d.count = len(b.register) + 1
+	return b.d
+}
+
+// dawgBuilder builds a DAWG from keys added in lexical order, see Daciuk et al.,
+// "Incremental Construction of Minimal Acyclic Finite-State Automata", 2000.
+type dawgBuilder struct {
+	d         *DAWG
+	register  map[string]*dstate
+	unchecked []dedgeFrom
+	prev      string
+}
+
+type dedgeFrom struct {
+	from *dstate
+	dedge
+}
+
+func (b *dawgBuilder) add(key string) {
+	common := 0
+	for common < len(key) && common < len(b.prev) && key[common] == b.prev[common] {
+		common++
+	}
+	b.minimize(common)
+	s := b.d.root
+	if len(b.unchecked) > 0 {
+		s = b.unchecked[len(b.unchecked)-1].to
+	}
+	for i := common; i < len(key); i++ {
+		next := &dstate{}
+		s.edges = append(s.edges, dedge{key[i], next})
+		b.unchecked = append(b.unchecked, dedgeFrom{s, dedge{key[i], next}})
+		s = next
+	}
+	s.final = true
+	b.d.len++
+	b.prev = key
+}
+
+// minimize replaces the unchecked states down to depth with equivalent registered ones.
+func (b *dawgBuilder) minimize(depth int) {
+	for i := len(b.unchecked) - 1; i >= depth; i-- {
+		u := b.unchecked[i]
+		sig := u.to.signature()
+		if s, ok := b.register[sig]; ok {
+			u.from.edges[len(u.from.edges)-1].to = s
+		} else {
+			u.to.id = len(b.register) + 1
+			b.register[sig] = u.to
+		}
+	}
+	b.unchecked = b.unchecked[:depth]
+}
+
+// signature returns a string that is equal for states with the same transitions. It holds
+// the final flag followed by the byte and the id of the target of each edge. An edge always
+// takes one byte and a uvarint id, so no two different states have the same signature.
+func (s *dstate) signature() string {
+	buf := make([]byte, 1, 1+len(s.edges)*(1+maxVarintLen64))
+	if s.final {
+		buf[0] = 1
+	}
+	for _, e := range s.edges {
+		buf = append(buf, e.b)
+		buf = appendUvarint(buf, uint64(e.to.id))
+	}
+	return string(buf)
+}
+
+func (s *dstate) next(b byte) *dstate {
+	for _, e := range s.edges {
+		if e.b == b {
+			return e.to
+		}
+	}
+	return nil
+}
+
+// walk follows key from the root and returns the state it ends in, or nil.
+func (d *DAWG) walk(key string) *dstate {
+	s := d.root
+	for i := 0; i < len(key) && s != nil; i++ {
+		s = s.next(key[i])
+	}
+	return s
+}
+
+// Len returns the number of keys in d.
+func (d *DAWG) Len() int { return d.len }
+
+// States returns the number of states in d.
+func (d *DAWG) States() int { return d.count }
+
+// Find returns true if key is in d.
+func (d *DAWG) Find(key string) bool {
+	s := d.walk(key)
+	return s != nil && s.final
+}
+
+// HasPrefix returns true if any key in d starts with prefix.
+func (d *DAWG) HasPrefix(prefix string) bool {
+	s := d.walk(prefix)
+	return s != nil && (s.final || len(s.edges) > 0)
+}
+
+// Prefix returns the keys in d that start with prefix, in lexical order.
+func (d *DAWG) Prefix(prefix string) []string {
+	var keys []string
+	s := d.walk(prefix)
+	if s == nil {
+		return keys
+	}
+	buf := []byte(prefix)
+	var collect func(s *dstate)
+	collect = func(s *dstate) {
+		if s.final {
+			keys = append(keys, string(buf))
+		}
+		for _, e := range s.edges {
+			buf = append(buf, e.b)
+			collect(e.to)
+			buf = buf[:len(buf)-1]
+		}
+	}
+	collect(s)
+	return keys
+}
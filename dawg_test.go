@@ -0,0 +1,80 @@
+package radix
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestDAWG(t *testing.T) {
+	r := New()
+	for _, stem := range []string{"walk", "talk", "jump", "play"} {
+		for _, suffix := range []string{"", "s", "ed", "ing"} {
+			r.Insert(stem+suffix, true)
+		}
+	}
+	d := r.DAWG()
+	if d.Len() != 16 {
+		t.Logf("DAWG should hold 16 keys, holds %d", d.Len())
+		t.Fail()
+	}
+	// The suffixes are shared, the trie would need 38 states.
+	if d.States() > 16 {
+		t.Logf("DAWG should share suffixes, has %d states", d.States())
+		t.Fail()
+	}
+	for _, k := range []string{"walked", "talks", "jump", "playing"} {
+		if !d.Find(k) {
+			t.Logf("%s should be found", k)
+			t.Fail()
+		}
+	}
+	for _, k := range []string{"walke", "talker", "", "x"} {
+		if d.Find(k) {
+			t.Logf("%s should not be found", k)
+			t.Fail()
+		}
+	}
+	if got := fmt.Sprint(d.Prefix("ta")); got != "[talk talked talking talks]" {
+		t.Logf("Prefix(ta) should be [talk talked talking talks], is %s", got)
+		t.Fail()
+	}
+	if !d.HasPrefix("pla") || d.HasPrefix("plu") {
+		t.Logf("HasPrefix(pla) should be true and HasPrefix(plu) false")
+		t.Fail()
+	}
+}
+
+func TestDAWGRandom(t *testing.T) {
+	const alphabet = "!123,"
+	for seed := int64(0); seed < 50; seed++ {
+		rnd := rand.New(rand.NewSource(seed))
+		r := New()
+		for i := 0; i < 200; i++ {
+			key := make([]byte, 1+rnd.Intn(6))
+			for j := range key {
+				key[j] = alphabet[rnd.Intn(len(alphabet))]
+			}
+			r.Insert(string(key), true)
+		}
+		d := r.DAWG()
+		if d.Len() != r.Len() {
+			t.Fatalf("seed %d: DAWG should hold %d keys, holds %d", seed, r.Len(), d.Len())
+		}
+		// Check all keys of up to 6 bytes over the alphabet against the tree.
+		var check func(key []byte)
+		check = func(key []byte) {
+			_, exact := r.Find(string(key))
+			if d.Find(string(key)) != (exact && len(key) > 0) {
+				t.Fatalf("seed %d: Find(%q) should be %t", seed, key, exact)
+			}
+			if len(key) == 6 {
+				return
+			}
+			for i := 0; i < len(alphabet); i++ {
+				check(append(key, alphabet[i]))
+			}
+		}
+		check(nil)
+	}
+}
@@ -0,0 +1,38 @@
+package radix
+
+// SetDefault sets the default value of the keys starting with prefix, see Get. A nil value
+// removes the default. r must be the root of the radix tree.
+func (r *Radix) SetDefault(prefix string, value interface{}) {
+	t := r.settings()
+	if t.defaults == nil {
+		t.defaults = New()
+	}
+	if prefix == "" {
+		t.defaults.set(value)
+		return
+	}
+	t.defaults.Insert(prefix, value)
+}
+
+// Get returns the value of key, or of the longest key in r that is a prefix of key, as found by
+// Find. If there is no such key, the default value set with SetDefault for the longest prefix of
+// key is returned, or nil if there is none. Values compressed by CompressValues are returned
+// uncompressed, or as nil if they can not be decompressed. r must be the root of the radix tree.
+func (r *Radix) Get(key string) interface{} {
+	if n, _ := r.Find(key); n != nil && n.Value != nil {
+		v, _ := plain(n.Value)
+		return v
+	}
+	t := r.tree
+	if t == nil || t.defaults == nil {
+		return nil
+	}
+	var value interface{}
+	t.defaults.prefixesOf(key, func(_ string, v interface{}) { value = v })
+	return value
+}
+
+// SetDefault sets the default value of the keys in the namespace, see Radix.SetDefault.
+func (ns *Namespace) SetDefault(value interface{}) {
+	ns.root.SetDefault(ns.prefix, value)
+}
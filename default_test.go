@@ -0,0 +1,35 @@
+package radix
+
+import (
+	"testing"
+)
+
+func TestDefault(t *testing.T) {
+	r := New()
+	r.Insert("/api/v1", "v1")
+	if v := r.Get("/other"); v != nil {
+		t.Logf("Get without defaults should return nil, returned %v", v)
+		t.Fail()
+	}
+	r.SetDefault("", "backend")
+	r.Namespace("/static/").SetDefault("cdn")
+	tests := map[string]interface{}{
+		"/api/v1":        "v1",
+		"/api/v1/users":  "v1", // longest prefix
+		"/api/v2":        "backend",
+		"/static/a.png":  "cdn",
+		"/staticfile":    "backend",
+		"/static/../etc": "cdn",
+	}
+	for key, want := range tests {
+		if v := r.Get(key); v != want {
+			t.Logf("Get(%s) should return %v, returned %v", key, want, v)
+			t.Fail()
+		}
+	}
+	r.SetDefault("/static/", nil)
+	if v := r.Get("/static/a.png"); v != "backend" {
+		t.Logf("after removing the default of /static/ Get should return backend, returned %v", v)
+		t.Fail()
+	}
+}
@@ -0,0 +1,133 @@
+package radix
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// ErrDelta is returned by WriteDelta when the changes since a revision are not known.
+var ErrDelta = errors.New("radix: changes since revision not known")
+
+var deltaMagic = [4]byte{'R', 'D', 'X', 'D'}
+
+// WriteDelta writes the changes made to r after revision rev to w, values are encoded with c.
+// A delta holds the keys inserted or changed since rev, found with ModifiedSince, and the keys
+// removed since rev, found with Tombstones. Applying it with ApplyDelta to a tree holding the
+// keys of r at revision rev, such as one read from a snapshot written then, brings that tree
+// up to date. The revision of r is recorded in the delta, use it as rev for the next delta.
+//
+// The tree must track modifications (TrackModifications) and keep tombstones (KeepTombstones)
+// since rev, and the tombstones after rev must not have expired, otherwise ErrDelta is
+// returned. r must be the root of the radix tree.
+func (r *Radix) WriteDelta(w io.Writer, rev uint64, c Codec, compress bool) error {
+	t := r.tree
+	if t == nil || !t.modifications || t.window == 0 {
+		return ErrDelta
+	}
+	tombstones := r.Tombstones(rev) // expires old tombstones first
+	if rev < t.tracked || rev < t.forgotten {
+		return wrap(ErrDelta, "revision "+strconv.FormatUint(rev, 10)+" too old")
+	}
+	changed := r.ModifiedSince(rev)
+	removed := make([]string, len(tombstones))
+	for i := range tombstones {
+		removed[i] = tombstones[i].Key
+	}
+	sort.Strings(removed)
+
+	flags := byte(0)
+	if compress {
+		flags |= snapshotCompressed
+	}
+	header := append(deltaMagic[:], flags)
+	header = appendUvarint(header, rev)
+	header = appendUvarint(header, t.rev)
+	body := w
+	var fw io.WriteCloser
+	if compress {
+		var err error
+		if fw, err = compressWriter(w); err != nil {
+			return err
+		}
+		body = fw
+	}
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	err := writeEntries(body, len(changed), func(f func(string, interface{}) error) error {
+		for _, n := range changed {
+			if err := f(n.Key(), n.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, c)
+	if err != nil {
+		return err
+	}
+	err = writeEntries(body, len(removed), func(f func(string, interface{}) error) error {
+		for _, k := range removed {
+			f(k, nil)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return err
+	}
+	if compress {
+		return fw.Close()
+	}
+	return nil
+}
+
+// ApplyDelta reads a delta written by WriteDelta and applies it to r, values are decoded with c.
+// It returns the revisions the delta was written from and at. Deltas must be applied in the
+// order they were written, on top of the snapshot they were taken from; the from revision of
+// each delta must equal the to revision of the previous one. r must be the root of the radix tree.
+func (r *Radix) ApplyDelta(rd io.Reader, c Codec) (from, to uint64, err error) {
+	br := bufio.NewReader(rd)
+	var header [5]byte
+	if _, err := io.ReadFull(br, header[:]); err != nil {
+		return 0, 0, ErrFormat
+	}
+	if [4]byte(header[:4]) != deltaMagic {
+		return 0, 0, ErrFormat
+	}
+	from, err1 := readUvarint(br)
+	to, err2 := readUvarint(br)
+	if err1 != nil || err2 != nil || from > to {
+		return 0, 0, ErrFormat
+	}
+	if header[4]&snapshotCompressed != 0 {
+		fr, err := decompressReader(br)
+		if err != nil {
+			return 0, 0, err
+		}
+		defer fr.Close()
+		br = bufio.NewReader(fr)
+	}
+	set := func(key string, value interface{}) {
+		if key == "" {
+			r.changeRoot(value)
+			return
+		}
+		r.Insert(key, value)
+	}
+	if err := readEntries(br, c, set); err != nil {
+		return 0, 0, err
+	}
+	remove := func(key string, _ interface{}) {
+		if key == "" {
+			r.changeRoot(nil)
+			return
+		}
+		r.Remove(key)
+	}
+	if err := readEntries(br, nil, remove); err != nil {
+		return 0, 0, err
+	}
+	return from, to, nil
+}
@@ -0,0 +1,122 @@
+package radix
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDelta(t *testing.T) {
+	for _, compress := range compressModes {
+		r := New()
+		r.TrackModifications()
+		r.KeepTombstones(time.Hour)
+		r.Insert("apple", "1")
+		r.Insert("apricot", "2")
+		r.Insert("banana", "3")
+
+		base := new(bytes.Buffer)
+		if err := r.WriteSnapshot(base, StringCodec{}, compress); err != nil {
+			t.Fatal(err)
+		}
+		rev := r.Revision()
+
+		r.Insert("apple", "one")
+		r.Remove("apricot")
+		r.Insert("cherry", "4")
+		delta1 := new(bytes.Buffer)
+		if err := r.WriteDelta(delta1, rev, StringCodec{}, compress); err != nil {
+			t.Fatal(err)
+		}
+		rev1 := r.Revision()
+
+		r.Remove("banana")
+		r.Insert("apricot", "again")
+		delta2 := new(bytes.Buffer)
+		if err := r.WriteDelta(delta2, rev1, StringCodec{}, compress); err != nil {
+			t.Fatal(err)
+		}
+
+		restored, err := ReadSnapshot(base, StringCodec{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		from, to, err := restored.ApplyDelta(delta1, StringCodec{})
+		if err != nil || from != rev || to != rev1 {
+			t.Fatalf("ApplyDelta: from %d, to %d, err %v", from, to, err)
+		}
+		if _, _, err := restored.ApplyDelta(delta2, StringCodec{}); err != nil {
+			t.Fatal(err)
+		}
+		if restored.Len() != r.Len() {
+			t.Fatalf("restored tree should hold %d keys, holds %d", r.Len(), restored.Len())
+		}
+		r.Walk(func(key string, value interface{}) error {
+			if n, exact := restored.Find(key); !exact || n.Value != value {
+				t.Logf("%s should be restored with value %v", key, value)
+				t.Fail()
+			}
+			return nil
+		})
+	}
+}
+
+func TestDeltaUnknown(t *testing.T) {
+	r := New()
+	r.KeepTombstones(time.Hour)
+	r.Insert("a", "1")
+	if err := r.WriteDelta(new(bytes.Buffer), 0, StringCodec{}, false); !errors.Is(err, ErrDelta) {
+		t.Logf("expected ErrDelta without tracking modifications, got %v", err)
+		t.Fail()
+	}
+	r.TrackModifications()
+	if err := r.WriteDelta(new(bytes.Buffer), 0, StringCodec{}, false); !errors.Is(err, ErrDelta) {
+		t.Logf("expected ErrDelta for a revision before tracking, got %v", err)
+		t.Fail()
+	}
+	if err := r.WriteDelta(new(bytes.Buffer), r.Revision(), StringCodec{}, false); err != nil {
+		t.Logf("expected no error, got %v", err)
+		t.Fail()
+	}
+}
+
+func TestDeltaBulkRemoval(t *testing.T) {
+	r := New()
+	r.KeepTombstones(time.Hour)
+	r.TrackModifications()
+	r.Insert("a", "1")
+	r.Insert("ns/b", "2")
+	r.Insert("ns/c", "3")
+	r.Tag("start")
+	base := new(bytes.Buffer)
+	if err := r.WriteSnapshot(base, StringCodec{}, false); err != nil {
+		t.Fatal(err)
+	}
+	rev := r.Revision()
+
+	r.Namespace("ns/").Clear()
+	delta := new(bytes.Buffer)
+	if err := r.WriteDelta(delta, rev, StringCodec{}, false); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := ReadSnapshot(base, StringCodec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := restored.ApplyDelta(delta, StringCodec{}); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Len() != 1 {
+		t.Logf("keys cleared from a namespace should be removed by the delta, got %d keys", restored.Len())
+		t.Fail()
+	}
+
+	rev = r.Revision()
+	r.Insert("d", "4")
+	r.Rollback("start")
+	if err := r.WriteDelta(new(bytes.Buffer), rev, StringCodec{}, false); !errors.Is(err, ErrDelta) {
+		t.Logf("expected ErrDelta for a revision before a rollback, got %v", err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,164 @@
+//go:build !tinygo
+
+package radix
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// ErrDecrypt is returned when encrypted data can not be decrypted, because the key is wrong or
+// the data has been changed or truncated.
+var ErrDecrypt = errors.New("radix: decryption failed")
+
+// Encrypted streams are written in chunks, each sealed with AES-GCM. The stream starts with a
+// random nonce prefix, each chunk has a header holding a flag for the last chunk and the length
+// of the sealed chunk. The header is authenticated and the chunk number is part of the nonce,
+// so chunks can not be reordered, dropped or flagged as the last one.
+const (
+	chunkSize   = 64 << 10
+	prefixSize  = 8 // random part of the nonce, the chunk number is the other 4 bytes
+	chunkHeader = 5 // last flag and length
+)
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+type encryptWriter struct {
+	w     io.Writer
+	aead  cipher.AEAD
+	nonce []byte
+	chunk uint32
+	buf   []byte
+}
+
+// EncryptWriter returns a writer that encrypts what is written to it with AES-GCM under key,
+// which must be 16, 24 or 32 bytes long, and writes it to w. The writer must be closed to
+// write the last chunk. Use it to encrypt snapshots, deltas and change feeds:
+//
+//	ew, err := radix.EncryptWriter(f, key)
+//	err = r.WriteSnapshot(ew, radix.StringCodec{}, true)
+//	err = ew.Close()
+func EncryptWriter(w io.Writer, key []byte) (io.WriteCloser, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	e := &encryptWriter{w: w, aead: aead, nonce: make([]byte, aead.NonceSize())}
+	if _, err := io.ReadFull(rand.Reader, e.nonce[:prefixSize]); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(e.nonce[:prefixSize]); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if len(e.buf) == chunkSize {
+			// only seal a full chunk when more data follows, the last chunk is sealed by Close
+			if err := e.seal(false); err != nil {
+				return n - len(p), err
+			}
+		}
+		m := chunkSize - len(e.buf)
+		if m > len(p) {
+			m = len(p)
+		}
+		e.buf = append(e.buf, p[:m]...)
+		p = p[m:]
+	}
+	return n, nil
+}
+
+// Close writes the last chunk. It does not close the underlying writer.
+func (e *encryptWriter) Close() error { return e.seal(true) }
+
+func (e *encryptWriter) seal(last bool) error {
+	header := make([]byte, chunkHeader, chunkHeader+len(e.buf)+e.aead.Overhead())
+	if last {
+		header[0] = 1
+	}
+	binary.BigEndian.PutUint32(header[1:], uint32(len(e.buf)+e.aead.Overhead()))
+	binary.BigEndian.PutUint32(e.nonce[prefixSize:], e.chunk)
+	e.chunk++
+	sealed := e.aead.Seal(header, e.nonce, e.buf, header)
+	e.buf = e.buf[:0]
+	_, err := e.w.Write(sealed)
+	return err
+}
+
+type decryptReader struct {
+	r     io.Reader
+	aead  cipher.AEAD
+	nonce []byte
+	chunk uint32
+	buf   []byte // decrypted data not read yet
+	last  bool   // the last chunk has been read
+}
+
+// DecryptReader returns a reader that decrypts the data written by EncryptWriter read from rd.
+// If the data can not be decrypted, Read returns an error wrapping ErrDecrypt. Use it to read
+// encrypted snapshots, deltas and change feeds:
+//
+//	dr, err := radix.DecryptReader(f, key)
+//	r, err := radix.ReadSnapshot(dr, radix.StringCodec{})
+func DecryptReader(rd io.Reader, key []byte) (io.Reader, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	d := &decryptReader{r: rd, aead: aead, nonce: make([]byte, aead.NonceSize())}
+	if _, err := io.ReadFull(rd, d.nonce[:prefixSize]); err != nil {
+		return nil, wrap(ErrDecrypt, "no nonce")
+	}
+	return d, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.last {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+func (d *decryptReader) open() error {
+	header := make([]byte, chunkHeader)
+	if _, err := io.ReadFull(d.r, header); err != nil {
+		return wrap(ErrDecrypt, "truncated")
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if header[0] > 1 || size > chunkSize+uint32(d.aead.Overhead()) {
+		return wrap(ErrDecrypt, "bad chunk")
+	}
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(d.r, sealed); err != nil {
+		return wrap(ErrDecrypt, "truncated")
+	}
+	binary.BigEndian.PutUint32(d.nonce[prefixSize:], d.chunk)
+	d.chunk++
+	b, err := d.aead.Open(sealed[:0], d.nonce, sealed, header)
+	if err != nil {
+		return ErrDecrypt
+	}
+	d.buf, d.last = b, header[0] == 1
+	return nil
+}
@@ -0,0 +1,67 @@
+//go:build !tinygo
+
+package radix
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+)
+
+func TestEncrypt(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	r := New()
+	for i := 0; i < 20000; i++ {
+		r.Insert("tenant/"+strconv.Itoa(i), strconv.Itoa(i)) // more than one chunk
+	}
+	var buf bytes.Buffer
+	ew, err := EncryptWriter(&buf, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.WriteSnapshot(ew, StringCodec{}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := ew.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("tenant/")) {
+		t.Fatalf("encrypted snapshot should not hold the keys in the clear")
+	}
+	data := buf.Bytes()
+
+	dr, err := DecryptReader(bytes.NewReader(data), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := ReadSnapshot(dr, StringCodec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Len() != r.Len() {
+		t.Logf("decrypted snapshot should hold %d keys, holds %d", r.Len(), s.Len())
+		t.Fail()
+	}
+
+	bad := map[string][]byte{
+		"wrong key": nil,
+		"truncated": data[:len(data)/2],
+		"changed":   append(append([]byte{}, data[:100]...), append([]byte{data[100] ^ 1}, data[101:]...)...),
+	}
+	for name, b := range bad {
+		k := key
+		if b == nil {
+			b, k = data, bytes.Repeat([]byte{8}, 32)
+		}
+		dr, err := DecryptReader(bytes.NewReader(b), k)
+		if err == nil {
+			_, err = io.ReadAll(dr)
+		}
+		if !errors.Is(err, ErrDecrypt) {
+			t.Logf("%s: reading should fail with ErrDecrypt, got %v", name, err)
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package radix
+
+// BloomFilter is a Bloom filter of the keys of a tree, see ExportFilter. It answers whether a
+// key may be in the tree: false is certain, true may be a false positive.
+type BloomFilter struct {
+	k    uint32 // number of hashes per key
+	bits []byte
+}
+
+// ExportFilter returns a BloomFilter of the keys of r using about bitsPerKey bits per key. With
+// 10 bits per key about 1% of the lookups of missing keys are false positives. Keys are relative
+// to r.
+func (r *Radix) ExportFilter(bitsPerKey int) *BloomFilter {
+	if bitsPerKey < 1 {
+		bitsPerKey = 1
+	}
+	n := r.Len()*bitsPerKey/8 + 1
+	f := &BloomFilter{k: uint32(bitsPerKey * 69 / 100), bits: make([]byte, n)} // k = bitsPerKey * ln(2)
+	if f.k < 1 {
+		f.k = 1
+	}
+	r.ordered("", func(key string, _ *Radix) bool {
+		f.add(key)
+		return true
+	})
+	return f
+}
+
+// MayContain returns false if key is not in the tree the filter was exported from, and true if
+// it may be.
+func (f *BloomFilter) MayContain(key string) bool {
+	h1, h2 := perfectHash(key, 0), perfectHash(key, 1)
+	m := uint64(len(f.bits)) * 8
+	for i := uint32(0); i < f.k; i++ {
+		b := (h1 + uint64(i)*h2) % m
+		if f.bits[b/8]&(1<<(b%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *BloomFilter) add(key string) {
+	h1, h2 := perfectHash(key, 0), perfectHash(key, 1)
+	m := uint64(len(f.bits)) * 8
+	for i := uint32(0); i < f.k; i++ {
+		b := (h1 + uint64(i)*h2) % m
+		f.bits[b/8] |= 1 << (b % 8)
+	}
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, so a filter can be sent to clients.
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, maxVarintLen64+len(f.bits))
+	buf = append(appendUvarint(buf, uint64(f.k)), f.bits...)
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	k, n := uvarint(data)
+	if n <= 0 || k == 0 || k > 64 || len(data) == n {
+		return ErrFormat
+	}
+	f.k = uint32(k)
+	f.bits = append([]byte(nil), data[n:]...)
+	return nil
+}
@@ -0,0 +1,42 @@
+package radix
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestExportFilter(t *testing.T) {
+	r := New()
+	for i := 0; i < 1000; i++ {
+		r.Insert("host"+strconv.Itoa(i)+".example.org", i)
+	}
+	data, err := r.ExportFilter(10).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := new(BloomFilter)
+	if err := f.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		if !f.MayContain("host" + strconv.Itoa(i) + ".example.org") {
+			t.Fatalf("host%d.example.org should be in the filter", i)
+		}
+	}
+	positives := 0
+	for i := 1000; i < 11000; i++ {
+		if f.MayContain("host" + strconv.Itoa(i) + ".example.org") {
+			positives++
+		}
+	}
+	// About 1% is expected.
+	if positives > 300 {
+		t.Logf("too many false positives: %d out of 10000", positives)
+		t.Fail()
+	}
+	if err := f.UnmarshalBinary(nil); !errors.Is(err, ErrFormat) {
+		t.Logf("unmarshaling nothing should fail with ErrFormat, got %v", err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,51 @@
+package radix
+
+// Finger looks up keys in a tree starting from the node it found last, instead of from the
+// root: the next Find starts from the deepest ancestor of that node whose key is a prefix of the
+// key searched for. This speeds up lookups that share long prefixes, such as sequential file
+// paths. A Finger is not safe for concurrent use, but each goroutine reading the tree may use
+// its own.
+type Finger struct {
+	root  *Radix
+	node  *Radix // node returned by the last Find
+	key   string // full key of node
+	epoch uint64 // epoch of the tree when node was found
+}
+
+// Finger returns a Finger for lookups in the tree r. Removals are counted in the settings of the
+// tree, which are created when r has none, so the first Finger must be taken before r is shared
+// between goroutines. r must be the root of the radix tree.
+func (r *Radix) Finger() *Finger {
+	r.settings() // removals are counted in the epoch
+	return &Finger{root: r}
+}
+
+// Find works like Radix.Find, but starts from the node found last. When nodes have been
+// removed from the tree since then, it starts from the root again.
+func (f *Finger) Find(key string) (node *Radix, exact bool) {
+	r, t := f.root, f.root.tree
+	if t.mounts != nil {
+		if m, rest := t.mounted(key); m != nil {
+			return m.find(rest)
+		}
+	}
+	start, skey := r, ""
+	if f.node != nil && f.epoch == t.epoch {
+		start, skey = f.node, f.key
+		for start != r && (len(skey) >= len(key) || key[:len(skey)] != skey) {
+			skey = skey[:len(skey)-len(start.key)]
+			start = start.parent
+		}
+	}
+	node, exact = start.find(key[len(skey):])
+	if node != nil && node != r {
+		f.node, f.epoch = node, t.epoch
+		if exact {
+			f.key = key
+		} else {
+			f.key = node.Key()
+		}
+	}
+	t.hit(node)
+	return node, exact
+}
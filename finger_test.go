@@ -0,0 +1,65 @@
+package radix
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestFinger(t *testing.T) {
+	r := New()
+	keys := []string{"usr/lib/a", "usr/lib/b", "usr/lib/c/d", "usr/bin/x", "usr", "etc/passwd"}
+	for _, k := range keys {
+		r.Insert(k, k)
+	}
+	f := r.Finger()
+	for i := 0; i < 2; i++ {
+		for _, k := range keys {
+			if n, exact := f.Find(k); !exact || n.Value != k {
+				t.Fatalf("%s should be found", k)
+			}
+		}
+	}
+	f.Find("usr/lib/c/d")
+	if f.node == nil || f.key != "usr/lib/c/d" {
+		t.Logf("finger should be usr/lib/c/d")
+		t.Fail()
+	}
+	if n, exact := f.Find("usr/lib/c/e"); exact || n.Key() != "usr" {
+		t.Logf("usr/lib/c/e should find usr")
+		t.Fail()
+	}
+	if n, _ := f.Find("var/log"); n != nil {
+		t.Logf("var/log should not be found")
+		t.Fail()
+	}
+	f.Find("usr/lib/b")
+	r.Remove("usr/lib/a") // merges usr/lib/b into its parent
+	if n, exact := f.Find("usr/lib/b"); !exact || n.Value != "usr/lib/b" || n.parent.children[n.key[0]] != n {
+		t.Logf("usr/lib/b should be found in the tree after Remove")
+		t.Fail()
+	}
+}
+
+func TestFingerConcurrent(t *testing.T) {
+	r := New()
+	for _, k := range []string{"usr/lib/a", "usr/lib/b", "usr/bin/x", "etc/passwd"} {
+		r.Insert(k, k)
+	}
+	r.Finger()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f := r.Finger()
+			for j := 0; j < 1000; j++ {
+				if n, exact := f.Find("usr/lib/a"); !exact || n.Value != "usr/lib/a" {
+					t.Errorf("usr/lib/a should be found")
+					return
+				}
+				f.Find("usr/bin/x")
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,42 @@
+//go:build !tinygo
+
+package radix
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+)
+
+// compressWriter returns a writer compressing to w with flate.
+func compressWriter(w io.Writer) (io.WriteCloser, error) {
+	return flate.NewWriter(w, flate.DefaultCompression)
+}
+
+// decompressReader returns a reader decompressing rd with flate.
+func decompressReader(rd io.Reader) (io.ReadCloser, error) {
+	return flate.NewReader(rd), nil
+}
+
+// FlateCompressor is a Compressor using compress/flate.
+type FlateCompressor struct{}
+
+// Compress implements Compressor.
+func (FlateCompressor) Compress(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	fw, _ := flate.NewWriter(&buf, flate.BestSpeed)
+	if _, err := fw.Write(b); err != nil {
+		return nil, err
+	}
+	if err := fw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress implements Compressor.
+func (FlateCompressor) Decompress(b []byte) ([]byte, error) {
+	fr := flate.NewReader(bytes.NewReader(b))
+	defer fr.Close()
+	return io.ReadAll(fr)
+}
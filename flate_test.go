@@ -0,0 +1,6 @@
+//go:build !tinygo
+
+package radix
+
+// compressModes are the values of the compress argument of WriteSnapshot and WriteDelta to test.
+var compressModes = []bool{false, true}
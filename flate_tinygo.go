@@ -0,0 +1,15 @@
+//go:build tinygo
+
+package radix
+
+import (
+	"errors"
+	"io"
+)
+
+// errNoFlate is returned for compressed snapshots and deltas, compress/flate is left out of
+// TinyGo builds, see the package documentation.
+var errNoFlate = errors.New("radix: flate compression is not available in this build")
+
+func compressWriter(w io.Writer) (io.WriteCloser, error)   { return nil, errNoFlate }
+func decompressReader(rd io.Reader) (io.ReadCloser, error) { return nil, errNoFlate }
@@ -0,0 +1,24 @@
+//go:build tinygo
+
+package radix
+
+import (
+	"bytes"
+	"testing"
+)
+
+var compressModes = []bool{false}
+
+func TestNoFlate(t *testing.T) {
+	r := New()
+	r.Insert("a", "1")
+	var buf bytes.Buffer
+	if err := r.WriteSnapshot(&buf, StringCodec{}, true); err != errNoFlate {
+		t.Logf("compressed snapshot should fail with errNoFlate, got %v", err)
+		t.Fail()
+	}
+	if buf.Len() != 0 {
+		t.Logf("nothing should be written for a failed snapshot")
+		t.Fail()
+	}
+}
@@ -0,0 +1,218 @@
+package radix
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// ErrGap is returned by a Follower when a record is missing from the change feed.
+var ErrGap = errors.New("radix: gap in change feed")
+
+// Record is an entry in a change feed: an operation and its sequence number. The sequence
+// numbers of a feed start at 1 and are incremented by one for each record.
+type Record struct {
+	Seq uint64
+	Op  Op
+}
+
+// OnChange makes the tree r call fn with a Record for each key changed by Insert, Remove and
+// the other functions that change keys one by one, such as Apply, SetUnder, RemoveMany,
+// DeleteRange, Graft, ApplyDelta, Undo and Redo. Applying the records to a Follower keeps it in
+// step with r. The records of a batch passed to Apply are only passed once the whole batch
+// succeeded. Values are passed uncompressed, see CompressValues. Rollback replaces the contents
+// of r without records: followers must be resynchronized after it, see Follower.Resync. A nil fn stops the feed. fn must not modify the tree. r must be the root
+// of the radix tree.
+func (r *Radix) OnChange(fn func(Record)) {
+	t := r.settings()
+	t.feed = fn
+}
+
+// emit passes the change of key to new, nil when key was removed, to the change feed.
+func (t *tree) emit(key string, new interface{}) {
+	if t.feed == nil || t.batching {
+		return
+	}
+	t.seq++
+	op := Op{Kind: OpRemove, Key: key}
+	if new != nil {
+		v, _ := plain(new)
+		op = Op{Kind: OpInsert, Key: key, Value: v}
+	}
+	t.feed(Record{Seq: t.seq, Op: op})
+}
+
+// WriteRecord writes rec to w, values are encoded with c. See Follower.Consume.
+func WriteRecord(w io.Writer, rec Record, c Codec) error {
+	var buf []byte
+	buf = appendUvarint(buf, rec.Seq)
+	buf = append(buf, byte(rec.Op.Kind))
+	buf = appendUvarint(buf, uint64(len(rec.Op.Key)))
+	buf = append(buf, rec.Op.Key...)
+	if rec.Op.Kind != OpRemove {
+		v, err := c.Encode(rec.Op.Value)
+		if err != nil {
+			return err
+		}
+		buf = appendUvarint(buf, uint64(len(v)))
+		buf = append(buf, v...)
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// readRecord reads a record written by WriteRecord.
+func readRecord(br *bufio.Reader, c Codec) (Record, error) {
+	var rec Record
+	seq, err := readUvarint(br)
+	if err == io.EOF {
+		return rec, io.EOF
+	}
+	if err != nil {
+		return rec, ErrFormat
+	}
+	kind, err := br.ReadByte()
+	if err != nil || OpKind(kind) > OpRemove {
+		return rec, ErrFormat
+	}
+	klen, err := readUvarint(br)
+	if err != nil {
+		return rec, ErrFormat
+	}
+	key, err := readBytes(br, klen)
+	if err != nil {
+		return rec, ErrFormat
+	}
+	rec.Seq, rec.Op = seq, Op{Kind: OpKind(kind), Key: string(key)}
+	if rec.Op.Kind == OpRemove {
+		return rec, nil
+	}
+	vlen, err := readUvarint(br)
+	if err != nil {
+		return rec, ErrFormat
+	}
+	v, err := readBytes(br, vlen)
+	if err != nil {
+		return rec, ErrFormat
+	}
+	rec.Op.Value, err = c.Decode(v)
+	return rec, err
+}
+
+// Follower keeps a read-only replica of a tree by applying the records of its change feed.
+// Records must be applied in order: when one is missing the follower refuses further records,
+// returning an error wrapping ErrGap, until it is resynchronized from a snapshot with Resync.
+// A Follower is safe for concurrent use.
+type Follower struct {
+	mu    sync.RWMutex
+	tree  *Radix
+	seq   uint64 // sequence number of the last applied record
+	codec Codec
+	gap   bool
+}
+
+// NewFollower returns a Follower with an empty replica, values are decoded with c. It expects
+// the record with sequence number 1 first.
+func NewFollower(c Codec) *Follower {
+	return &Follower{tree: New(), codec: c}
+}
+
+// Seq returns the sequence number of the last applied record.
+func (f *Follower) Seq() uint64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.seq
+}
+
+// Find looks up key in the replica, see Radix.Find. Instead of the node, which may change as
+// soon as the next record is applied, the key and value of the node found are returned.
+func (f *Follower) Find(key string) (match string, value interface{}, exact bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	n, exact := f.tree.find(key)
+	if n == nil {
+		return "", nil, false
+	}
+	return n.Key(), n.Uncompressed(), exact
+}
+
+// Do calls fn with the replica while no records are applied. fn must not modify the tree, and
+// must not keep any of its nodes after it returns, records are applied to them in place.
+func (f *Follower) Do(fn func(r *Radix)) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	fn(f.tree)
+}
+
+// Apply applies rec to the replica. Records that have already been applied are ignored. If
+// records are missing before rec, an error wrapping ErrGap is returned and rec is not applied.
+func (f *Follower) Apply(rec Record) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if rec.Seq <= f.seq {
+		return nil
+	}
+	if f.gap || rec.Seq != f.seq+1 {
+		f.gap = true
+		return wrap(ErrGap, "expected "+strconv.FormatUint(f.seq+1, 10)+", got "+strconv.FormatUint(rec.Seq, 10))
+	}
+	switch {
+	case rec.Op.Key == "":
+		// The value of the root, PopMin and PopMax may remove it.
+		if rec.Op.Kind == OpRemove {
+			f.tree.setRoot(nil)
+		} else {
+			f.tree.setRoot(rec.Op.Value)
+		}
+	default:
+		if err := f.tree.Apply([]Op{rec.Op}); err != nil {
+			return err
+		}
+	}
+	f.seq = rec.Seq
+	return nil
+}
+
+// Follow applies the records received from ch until ch is closed or an error occurs.
+func (f *Follower) Follow(ch <-chan Record) error {
+	for rec := range ch {
+		if err := f.Apply(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Consume applies the records written with WriteRecord read from rd, until the end of rd or
+// an error occurs.
+func (f *Follower) Consume(rd io.Reader) error {
+	br := bufio.NewReader(rd)
+	for {
+		rec, err := readRecord(br, f.codec)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := f.Apply(rec); err != nil {
+			return err
+		}
+	}
+}
+
+// Resync replaces the replica by the snapshot read from rd, written by WriteSnapshot after the
+// record with sequence number seq was applied to the tree. Records up to seq are ignored from
+// now on.
+func (f *Follower) Resync(rd io.Reader, seq uint64) error {
+	r, err := ReadSnapshot(rd, f.codec)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tree, f.seq, f.gap = r, seq, false
+	return nil
+}
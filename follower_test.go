@@ -0,0 +1,147 @@
+package radix
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFollower(t *testing.T) {
+	leader := New()
+	var feed bytes.Buffer
+	var seq uint64
+	emit := func(op Op) {
+		if err := leader.Apply([]Op{op}); err != nil {
+			t.Fatal(err)
+		}
+		seq++
+		if err := WriteRecord(&feed, Record{Seq: seq, Op: op}, StringCodec{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	emit(Op{Kind: OpInsert, Key: "a", Value: "1"})
+	emit(Op{Kind: OpInsert, Key: "b", Value: "2"})
+	emit(Op{Kind: OpRemove, Key: "a"})
+
+	f := NewFollower(StringCodec{})
+	if err := f.Consume(&feed); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, exact := f.Find("a"); exact {
+		t.Logf("a should be removed on the follower")
+		t.Fail()
+	}
+	if key, value, exact := f.Find("b"); !exact || key != "b" || value != "2" || f.Seq() != 3 {
+		t.Logf("follower should hold b at sequence 3, is at %d", f.Seq())
+		t.Fail()
+	}
+
+	// Record 4 is lost.
+	emit(Op{Kind: OpInsert, Key: "c", Value: "3"})
+	feed.Reset()
+	emit(Op{Kind: OpInsert, Key: "d", Value: "4"})
+	if err := f.Consume(&feed); !errors.Is(err, ErrGap) {
+		t.Fatalf("missing record should fail with ErrGap, got %v", err)
+	}
+	if err := f.Apply(Record{Seq: 6, Op: Op{Kind: OpInsert, Key: "e", Value: "5"}}); !errors.Is(err, ErrGap) {
+		t.Logf("follower should refuse records until resynced, got %v", err)
+		t.Fail()
+	}
+
+	var snap bytes.Buffer
+	if err := leader.WriteSnapshot(&snap, StringCodec{}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Resync(&snap, seq); err != nil {
+		t.Fatal(err)
+	}
+	ch := make(chan Record, 2)
+	ch <- Record{Seq: 5, Op: Op{Kind: OpInsert, Key: "d", Value: "4"}} // already in the snapshot
+	ch <- Record{Seq: 6, Op: Op{Kind: OpInsert, Key: "e", Value: "5"}}
+	close(ch)
+	if err := f.Follow(ch); err != nil {
+		t.Fatal(err)
+	}
+	f.Do(func(r *Radix) {
+		if r.Len() != 4 {
+			t.Logf("follower should hold b c d e, holds %d keys", r.Len())
+			t.Fail()
+		}
+	})
+}
+
+func TestFollowerConcurrent(t *testing.T) {
+	f := NewFollower(StringCodec{})
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 1000; i++ {
+			f.Find("ab")
+		}
+		close(done)
+	}()
+	for i := uint64(1); i <= 1000; i++ {
+		op := Op{Kind: OpInsert, Key: []string{"a", "ab", "abc"}[i%3], Value: "v"}
+		if i%2 == 0 {
+			op = Op{Kind: OpRemove, Key: op.Key}
+		}
+		if err := f.Apply(Record{Seq: i, Op: op}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	<-done
+}
+
+func TestFollowerCorrupt(t *testing.T) {
+	feed := []byte{1, byte(OpInsert)}
+	feed = appendUvarint(feed, 1<<62-1) // key length
+	if err := NewFollower(StringCodec{}).Consume(bytes.NewReader(feed)); !errors.Is(err, ErrFormat) {
+		t.Logf("corrupt record should fail with ErrFormat, got %v", err)
+		t.Fail()
+	}
+}
+
+func TestOnChange(t *testing.T) {
+	leader := New()
+	leader.KeepHistory(10)
+	var feed bytes.Buffer
+	var err error
+	leader.OnChange(func(rec Record) {
+		if err == nil {
+			err = WriteRecord(&feed, rec, StringCodec{})
+		}
+	})
+	long := strings.Repeat("long value ", 10)
+	for _, k := range []string{"a", "ab", "abc", "b", "ba", "c/1", "c/2", "d/1", "d/2", "e"} {
+		leader.Insert(k, long+k)
+	}
+	leader.Remove("ab")
+	leader.SetUnder("b", func(key string, old interface{}) interface{} { return old.(string) + "!" })
+	leader.RemoveMany([]string{"a", "e"})
+	leader.DeleteRange("c/", "c/2")
+	leader.Apply([]Op{{Kind: OpInsert, Key: "f", Value: "f"}, {Kind: OpCreate, Key: "b", Value: "b"}}) // fails as a whole
+	leader.Apply([]Op{{Kind: OpInsert, Key: "g", Value: "g"}, {Kind: OpRemove, Key: "ba"}})
+	leader.Namespace("d/").Clear()
+	sub := New()
+	sub.Insert("1", "h1")
+	sub.Insert("2", "h2")
+	leader.Graft("h/", sub)
+	leader.Undo(2)
+	leader.Redo(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := NewFollower(StringCodec{})
+	if err := f.Consume(&feed); err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprint(keysOf(leader))
+	f.Do(func(r *Radix) {
+		if got := fmt.Sprint(keysOf(r)); got != want {
+			t.Logf("follower holds\n%s\nleader holds\n%s", got, want)
+			t.Fail()
+		}
+	})
+}
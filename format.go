@@ -0,0 +1,73 @@
+//go:build !tinygo
+
+package radix
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Format implements fmt.Formatter. The verb %v prints a summary of the tree r: the number
+// of keys and the depth. The verb %+v prints the nodes of r, one per line, sorted and indented
+// per level, each with its key relative to its parent and its Value, if set. A precision limits
+// the number of levels printed, %+.2v prints two levels and %+.0v only the number of keys
+// below r. The verb %s prints String().
+func (r *Radix) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 's':
+		fmt.Fprint(f, r.String())
+	case verb == 'v' && f.Flag('+'):
+		depth, ok := f.Precision()
+		if !ok {
+			depth = -1
+		}
+		var b strings.Builder
+		if depth == 0 {
+			if len(r.children) > 0 {
+				fmt.Fprintf(&b, "(%d more below)\n", r.Len()-btoi(r.Value != nil))
+			}
+		} else {
+			r.format(&b, "", depth)
+		}
+		fmt.Fprint(f, b.String())
+	default:
+		fmt.Fprintf(f, "radix: %d keys, depth %d", r.Len(), r.Depth())
+	}
+}
+
+// Depth returns the number of levels below r: the length of the longest path from r to a leaf.
+func (r *Radix) Depth() int {
+	d := 0
+	for _, child := range r.children {
+		if c := child.Depth() + 1; c > d {
+			d = c
+		}
+	}
+	return d
+}
+
+// format writes the nodes below r, up to depth levels; a negative depth has no limit.
+func (r *Radix) format(b *strings.Builder, indent string, depth int) {
+	for _, k := range sortedChildren(r.children) {
+		child := r.children[k]
+		b.WriteString(indent)
+		b.WriteString(child.key)
+		if child.Value != nil {
+			fmt.Fprintf(b, ": %v", child.Uncompressed())
+		}
+		if depth == 1 && len(child.children) > 0 {
+			fmt.Fprintf(b, " (%d more below)", child.Len()-btoi(child.Value != nil))
+		}
+		b.WriteByte('\n')
+		if depth != 1 {
+			child.format(b, indent+"  ", depth-1)
+		}
+	}
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
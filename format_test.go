@@ -0,0 +1,40 @@
+//go:build !tinygo
+
+package radix
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	r := radixtree()
+	r.Insert("toast", "b")
+	if s := fmt.Sprintf("%v", r); s != "radix: 5 keys, depth 4" {
+		t.Logf("%%v should be a summary, is %q", s)
+		t.Fail()
+	}
+	want := `t
+  e: a
+    am: a
+    st: a
+      er: a
+  oast: b
+`
+	if s := fmt.Sprintf("%+v", r); s != want {
+		t.Logf("%%+v should be\n%s, is\n%s", want, s)
+		t.Fail()
+	}
+	want = `t
+  e: a (3 more below)
+  oast: b
+`
+	if s := fmt.Sprintf("%+.2v", r); s != want {
+		t.Logf("%%+.2v should be\n%s, is\n%s", want, s)
+		t.Fail()
+	}
+	if s := fmt.Sprintf("%+.0v", r); s != "(5 more below)\n" {
+		t.Logf("%%+.0v should only print the number of keys, is\n%s", s)
+		t.Fail()
+	}
+}
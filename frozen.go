@@ -0,0 +1,269 @@
+package radix
+
+import (
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// Frozen is a read-only tree of keys and encoded values that is used in place: it is opened on
+// the data written by WriteFrozen, and lookups read that data directly. Keys and values are not
+// copied, so a table embedded in the binary,
+//
+//	//go:embed table.rdx
+//	var table string
+//
+// is opened with OpenFrozen(table) without reading it onto the heap. A table in an fs.FS, such
+// as an embed.FS, is opened with OpenFrozenFS, which reads the parts of the file a lookup needs.
+//
+// The keys are stored in lexical order, so besides exact lookups with Find, a Frozen answers
+// the longest prefix match with LongestPrefix and walks the keys, or the keys starting with a
+// prefix, in order with Walk and WalkPrefix. Each lookup is a binary search over the keys.
+type Frozen struct {
+	n    int         // number of keys
+	data string      // the table, when opened with OpenFrozen
+	ra   io.ReaderAt // the table, when opened with OpenFrozenFS
+	size uint64      // length of the table
+	file fs.File     // closed by Close
+}
+
+var frozenMagic = "RDXF"
+
+// WriteFrozen writes the keys of r and their values, encoded with c, to w in the layout read by
+// OpenFrozen. Keys are relative to r.
+//
+// The layout starts with the magic "RDXF" and the number of keys n, followed by n+1 offsets of
+// the entries, relative to the first entry, and the entries in lexical order of their keys. An
+// entry is the length of the key, the key and the encoded value. All numbers are little endian
+// uint32s.
+func (r *Radix) WriteFrozen(w io.Writer, c Codec) error {
+	var offsets, entries []byte
+	n := uint32(0)
+	err := r.Walk(func(key string, value interface{}) error {
+		offsets = appendLE32(offsets, uint32(len(entries)))
+		v, err := c.Encode(value)
+		if err != nil {
+			return err
+		}
+		entries = appendLE32(entries, uint32(len(key)))
+		entries = append(append(entries, key...), v...)
+		if uint64(len(entries)) > 1<<32-1 {
+			return wrap(ErrLimit, "frozen table larger than 4 GiB")
+		}
+		n++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	offsets = appendLE32(offsets, uint32(len(entries)))
+	header := appendLE32([]byte(frozenMagic), n)
+	for _, b := range [][]byte{header, offsets, entries} {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OpenFrozen returns the Frozen tree held in data, written by WriteFrozen. Only the header is
+// checked; an entry that turns out to be corrupt is not found.
+func OpenFrozen(data string) (*Frozen, error) {
+	f := &Frozen{data: data, size: uint64(len(data))}
+	return f, f.open()
+}
+
+// OpenFrozenFS opens the Frozen tree in the file name in fsys. If the file implements
+// io.ReaderAt, as the files of embed.FS and os.DirFS do, the table is not read up front: each
+// lookup reads the offsets and entries it needs, so neither the table nor a copy of it is held on
+// the heap. Other files are read in full. The file stays open until Close is called.
+func OpenFrozenFS(fsys fs.FS, name string) (*Frozen, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	f := &Frozen{file: file}
+	if ra, ok := file.(io.ReaderAt); ok {
+		fi, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		f.ra, f.size = ra, uint64(fi.Size())
+	} else {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		f.data, f.size = string(data), uint64(len(data))
+	}
+	if err := f.open(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// open checks the header of the table of f.
+func (f *Frozen) open() error {
+	header, ok := f.read(0, 8)
+	if !ok || header[:4] != frozenMagic {
+		return ErrFormat
+	}
+	f.n = int(le32(header, 4))
+	base := 8 + 4*(uint64(f.n)+1)
+	last, ok := f.read(base-4, 4)
+	if !ok {
+		return wrap(ErrFormat, "frozen table truncated")
+	}
+	if base+uint64(le32(last, 0)) != f.size {
+		return wrap(ErrFormat, "frozen table size "+strconv.FormatUint(f.size, 10))
+	}
+	return nil
+}
+
+// Close closes the file f was opened on by OpenFrozenFS. It does nothing for a Frozen opened by
+// OpenFrozen.
+func (f *Frozen) Close() error {
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Close()
+}
+
+// read returns the n bytes of the table at off. For a table read through an io.ReaderAt they
+// are copied, otherwise they are part of the data f was opened on.
+func (f *Frozen) read(off, n uint64) (string, bool) {
+	if off+n < off || off+n > f.size {
+		return "", false
+	}
+	if f.ra == nil {
+		return f.data[off : off+n], true
+	}
+	b := make([]byte, n)
+	if _, err := f.ra.ReadAt(b, int64(off)); err != nil {
+		return "", false
+	}
+	return string(b), true
+}
+
+// entry returns the key and value of entry i.
+func (f *Frozen) entry(i int) (key, value string, ok bool) {
+	offsets, ok := f.read(8+4*uint64(i), 8)
+	if !ok {
+		return "", "", false
+	}
+	start, end := le32(offsets, 0), le32(offsets, 4)
+	if start > end || end-start < 4 {
+		return "", "", false
+	}
+	e, ok := f.read(8+4*(uint64(f.n)+1)+uint64(start), uint64(end-start))
+	if !ok {
+		return "", "", false
+	}
+	klen := le32(e, 0)
+	if uint64(klen) > uint64(len(e)-4) {
+		return "", "", false
+	}
+	return e[4 : 4+klen], e[4+klen:], true
+}
+
+// search returns the smallest i in [0, n) for which pred is true of the key of entry i, or n.
+// pred must be false for a prefix of the entries and true for the rest.
+func (f *Frozen) search(pred func(key string) bool) int {
+	lo, hi := 0, f.n
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if key, _, _ := f.entry(mid); !pred(key) {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// Len returns the number of keys in f.
+func (f *Frozen) Len() int { return f.n }
+
+// Find returns the encoded value of key and true, or the empty string and false if f does not
+// hold key. The value is part of the data f was opened on, decode it with the Codec given to
+// WriteFrozen; with StringCodec it is the value itself.
+func (f *Frozen) Find(key string) (string, bool) {
+	i := f.search(func(k string) bool { return k >= key })
+	if i == f.n {
+		return "", false
+	}
+	k, v, ok := f.entry(i)
+	if !ok || k != key {
+		return "", false
+	}
+	return v, true
+}
+
+// LongestPrefix returns the longest key in f that is a prefix of key, and its encoded value. If
+// no key in f is a prefix of key, ok is false.
+func (f *Frozen) LongestPrefix(key string) (prefix, value string, ok bool) {
+	for {
+		i := f.search(func(k string) bool { return k > key }) - 1
+		if i < 0 {
+			return "", "", false
+		}
+		k, v, ok := f.entry(i)
+		if !ok {
+			return "", "", false
+		}
+		if strings.HasPrefix(key, k) {
+			return k, v, true
+		}
+		// A key of f that is a prefix of key and sorts before k is a prefix of both.
+		_, l := longestCommonPrefix(k, key)
+		key = key[:l]
+	}
+}
+
+// Walk calls fn for each key in f and its encoded value, in lexical order. If fn returns
+// SkipSubtree, the keys that have the current key as a prefix are skipped. If fn returns
+// another error the walk stops and that error is returned.
+func (f *Frozen) Walk(fn func(key, value string) error) error {
+	return f.WalkPrefix("", fn)
+}
+
+// WalkPrefix works like Walk, but only visits the keys starting with prefix.
+func (f *Frozen) WalkPrefix(prefix string, fn func(key, value string) error) error {
+	i, end := f.span(prefix)
+	for i < end {
+		k, v, ok := f.entry(i)
+		if !ok {
+			return wrap(ErrFormat, "frozen entry "+strconv.Itoa(i))
+		}
+		switch err := fn(k, v); err {
+		case nil:
+			i++
+		case SkipSubtree:
+			_, i = f.span(k)
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// span returns the range [start, end) of the entries whose keys start with prefix.
+func (f *Frozen) span(prefix string) (start, end int) {
+	start = f.search(func(k string) bool { return k >= prefix })
+	end = f.search(func(k string) bool { return k >= prefix && !strings.HasPrefix(k, prefix) })
+	return start, end
+}
+
+// le32 returns the little endian uint32 at s[i:].
+func le32(s string, i int) uint32 {
+	return uint32(s[i]) | uint32(s[i+1])<<8 | uint32(s[i+2])<<16 | uint32(s[i+3])<<24
+}
+
+// appendLE32 appends x to b as a little endian uint32.
+func appendLE32(b []byte, x uint32) []byte {
+	return append(b, byte(x), byte(x>>8), byte(x>>16), byte(x>>24))
+}
@@ -0,0 +1,200 @@
+package radix
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFrozen(t *testing.T) {
+	r := New()
+	for i := 0; i < 1000; i++ {
+		r.Insert("key/"+strconv.Itoa(i), strconv.Itoa(i))
+	}
+	var buf bytes.Buffer
+	if err := r.WriteFrozen(&buf, StringCodec{}); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.String()
+	f, err := OpenFrozen(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Len() != 1000 {
+		t.Fatalf("index should hold 1000 keys, holds %d", f.Len())
+	}
+	for i := 0; i < 1000; i++ {
+		if v, ok := f.Find("key/" + strconv.Itoa(i)); !ok || v != strconv.Itoa(i) {
+			t.Fatalf("key/%d should be found with value %d, found %q", i, i, v)
+		}
+	}
+	for _, k := range []string{"key/1000", "key/", "", "other"} {
+		if _, ok := f.Find(k); ok {
+			t.Logf("%q should not be found", k)
+			t.Fail()
+		}
+	}
+	if n := testing.AllocsPerRun(100, func() { f.Find("key/500") }); n != 0 {
+		t.Logf("Find should not allocate, allocates %v times", n)
+		t.Fail()
+	}
+
+	// Corrupt entries are not found, they do not panic.
+	b := []byte(data)
+	for i := len(b) - 4000; i < len(b); i += 7 {
+		b[i] ^= 0xff
+	}
+	if f, err := OpenFrozen(string(b)); err == nil {
+		for i := 0; i < 1000; i++ {
+			f.Find("key/" + strconv.Itoa(i))
+		}
+	}
+	for _, bad := range []string{"", "RDXF", data[:100], "RDXS" + data[4:]} {
+		if _, err := OpenFrozen(bad); !errors.Is(err, ErrFormat) {
+			t.Logf("opening %d bytes of bad data should fail with ErrFormat, got %v", len(bad), err)
+			t.Fail()
+		}
+	}
+}
+
+func TestFrozenEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := New().WriteFrozen(&buf, StringCodec{}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := OpenFrozen(buf.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := f.Find("a"); ok {
+		t.Logf("empty index should not find anything")
+		t.Fail()
+	}
+}
+
+// frozen returns the Frozen tree of keys, whose values are the keys themselves.
+func frozen(t *testing.T, keys ...string) *Frozen {
+	r := New()
+	for _, k := range keys {
+		r.Insert(k, k)
+	}
+	var buf bytes.Buffer
+	if err := r.WriteFrozen(&buf, StringCodec{}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := OpenFrozen(buf.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestFrozenLongestPrefix(t *testing.T) {
+	f := frozen(t, "com", "example.com", "www.example.com", "org", "a", "abc")
+	for key, want := range map[string]string{
+		"www.example.com": "www.example.com",
+		"www.example.co":  "",
+		"example.com/x":   "example.com",
+		"example.co":      "",
+		"com.au":          "com",
+		"abd":             "a",
+		"abcd":            "abc",
+		"b":               "",
+		"":                "",
+	} {
+		prefix, value, ok := f.LongestPrefix(key)
+		if ok != (want != "") || prefix != want || value != want {
+			t.Logf("LongestPrefix(%q) should be %q, is %q %q %t", key, want, prefix, value, ok)
+			t.Fail()
+		}
+	}
+}
+
+func TestFrozenWalk(t *testing.T) {
+	f := frozen(t, "a", "a/1", "a/2", "a/2/x", "b", "b/1", "c")
+	walk := func(prefix string, skip string) string {
+		var keys []string
+		f.WalkPrefix(prefix, func(key, value string) error {
+			if key != value {
+				t.Logf("key %q has value %q", key, value)
+				t.Fail()
+			}
+			keys = append(keys, key)
+			if key == skip {
+				return SkipSubtree
+			}
+			return nil
+		})
+		return strings.Join(keys, " ")
+	}
+	for _, tc := range []struct{ prefix, skip, want string }{
+		{"", "", "a a/1 a/2 a/2/x b b/1 c"},
+		{"a/", "", "a/1 a/2 a/2/x"},
+		{"", "a", "a b b/1 c"},
+		{"a", "a/2", "a a/1 a/2"},
+		{"b/", "", "b/1"},
+		{"d", "", ""},
+	} {
+		if got := walk(tc.prefix, tc.skip); got != tc.want {
+			t.Logf("WalkPrefix(%q) skipping %q should visit %q, visits %q", tc.prefix, tc.skip, tc.want, got)
+			t.Fail()
+		}
+	}
+	stop := errors.New("stop")
+	n := 0
+	if err := f.Walk(func(string, string) error { n++; return stop }); err != stop || n != 1 {
+		t.Logf("Walk should stop at the first error, got %v after %d keys", err, n)
+		t.Fail()
+	}
+}
+
+// noReaderAt hides the ReadAt method of the files of an fs.FS.
+type noReaderAt struct{ fs.FS }
+
+func (n noReaderAt) Open(name string) (fs.File, error) {
+	f, err := n.FS.Open(name)
+	return struct{ fs.File }{f}, err
+}
+
+func TestOpenFrozenFS(t *testing.T) {
+	r := New()
+	for i := 0; i < 100; i++ {
+		r.Insert("key/"+strconv.Itoa(i), strconv.Itoa(i))
+	}
+	var buf bytes.Buffer
+	if err := r.WriteFrozen(&buf, StringCodec{}); err != nil {
+		t.Fatal(err)
+	}
+	fsys := fstest.MapFS{"table.rdx": &fstest.MapFile{Data: buf.Bytes()}, "bad.rdx": &fstest.MapFile{Data: []byte("RDXF")}}
+	for name, fsys := range map[string]fs.FS{"ReaderAt": fsys, "Reader": noReaderAt{fsys}} {
+		f, err := OpenFrozenFS(fsys, "table.rdx")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (f.ra != nil) != (name == "ReaderAt") {
+			t.Logf("%s: table should be read through an io.ReaderAt only when the file is one", name)
+			t.Fail()
+		}
+		if v, ok := f.Find("key/42"); !ok || v != "42" {
+			t.Logf("%s: key/42 should be found with value 42, found %q", name, v)
+			t.Fail()
+		}
+		if prefix, _, ok := f.LongestPrefix("key/420"); !ok || prefix != "key/42" {
+			t.Logf("%s: LongestPrefix(key/420) should be key/42, is %q", name, prefix)
+			t.Fail()
+		}
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+		for _, bad := range []string{"bad.rdx", "missing.rdx"} {
+			if _, err := OpenFrozenFS(fsys, bad); err == nil {
+				t.Logf("%s: opening %s should fail", name, bad)
+				t.Fail()
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package radix
+
+import (
+	"strconv"
+)
+
+// Graft attaches the tree sub under prefix in r: each key k in sub is
+// available as prefix+k in r afterwards. If any of these keys is already present in r
+// an error wrapping ErrExists is returned and r is not modified.
+// When nothing is stored under prefix in r, the nodes of sub are moved into r without
+// copying, sub must not be used after a successful Graft. r must be the root of the radix tree.
+func (r *Radix) Graft(prefix string, sub *Radix) error {
+	return r.GraftFunc(prefix, sub, nil)
+}
+
+// GraftFunc works like Graft, but calls resolve for each key present in both trees.
+// The value returned by resolve is stored under key. If resolve is nil, GraftFunc
+// behaves just as Graft. If the keys of sub would exceed the limits or quotas of r, the error
+// of TryInsert is returned and r is not modified.
+func (r *Radix) GraftFunc(prefix string, sub *Radix, resolve func(key string, old, new interface{}) interface{}) error {
+	if !sub.hasValue() {
+		return nil
+	}
+	if resolve == nil {
+		var err error
+		sub.walk(prefix, func(key string, n *Radix) {
+			if _, exact := r.find(key); exact && err == nil {
+				err = wrap(ErrExists, key)
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	n := r.node(prefix)
+	if n == nil && r.under(prefix) == nil || n != nil && len(n.children) == 0 && (n.Value == nil || sub.Value == nil) {
+		// Nothing below prefix, splice the children of sub into r.
+		if err := r.checkGraft(prefix, sub); err != nil {
+			return err
+		}
+		if n == nil {
+			var err error
+			if n, err = r.TryInsert(prefix, nil); err != nil {
+				return err
+			}
+		}
+		if t := r.tree; t != nil && t.compressor != nil {
+			sub.walk("", func(_ string, s *Radix) { s.Value = t.compress(s.Value) })
+		}
+		size := 0
+		for _, child := range sub.children {
+			child.parent = n
+			n.addChild(child)
+			size += child.size
+		}
+		n.grow(size)
+		if sub.Value != nil {
+			n.set(sub.Value)
+		}
+		sub.children = nil
+		sub.resize()
+		if t := r.tree; t != nil {
+			t.rev++
+			t.stamp(n)
+			n.walk(prefix, func(key string, s *Radix) {
+				if s != n || sub.Value != nil {
+					t.emit(key, s.Value)
+				}
+			})
+		}
+		r.recount()
+		return nil
+	}
+
+	// Insert the keys one by one, all or nothing.
+	var batch []Op
+	var root interface{}
+	sub.walk(prefix, func(key string, s *Radix) {
+		value := s.Value
+		if old, exact := r.find(key); exact {
+			value = resolve(key, old.Uncompressed(), s.Uncompressed())
+		}
+		if key == "" {
+			root = value
+			return
+		}
+		batch = append(batch, Op{Kind: OpInsert, Key: key, Value: value})
+	})
+	if err := r.Apply(batch); err != nil {
+		return err
+	}
+	if root != nil {
+		r.changeRoot(root)
+	}
+	return nil
+}
+
+// checkGraft returns an error if splicing sub under prefix, where r holds no keys, exceeds the
+// limits or quotas of r.
+func (r *Radix) checkGraft(prefix string, sub *Radix) error {
+	t := r.tree
+	if t == nil {
+		return nil
+	}
+	l := t.limits
+	keys, nodes := 0, r.newNodes(prefix)-1 // the root of sub is not moved
+	quotas := make(map[*quota]int)
+	var err error
+	var count func(key string, n *Radix)
+	count = func(key string, n *Radix) {
+		nodes++
+		if n.Value != nil {
+			keys++
+			if l.MaxKeyLen > 0 && len(key) > l.MaxKeyLen && err == nil {
+				err = wrap(ErrLimit, "key length "+strconv.Itoa(len(key))+" > "+strconv.Itoa(l.MaxKeyLen))
+			}
+			t.quotasOf(key, func(p string, q *quota) {
+				quotas[q]++
+				if q.count+quotas[q] > q.max && err == nil {
+					err = &QuotaError{Prefix: p, Max: q.max}
+				}
+			})
+		}
+		for _, child := range n.children {
+			count(key+child.key, child)
+		}
+	}
+	count(prefix, sub)
+	switch {
+	case err != nil:
+		return err
+	case l.MaxKeys > 0 && keys > 0 && t.keys+keys > l.MaxKeys:
+		return wrap(ErrLimit, "more than "+strconv.Itoa(l.MaxKeys)+" keys")
+	case l.MaxNodes > 0 && nodes > 0 && t.nodes+nodes > l.MaxNodes:
+		return wrap(ErrLimit, "more than "+strconv.Itoa(l.MaxNodes)+" nodes")
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package radix
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGraft(t *testing.T) {
+	r := radixtree()
+	sub := New()
+	sub.Insert("a", "a")
+	sub.Insert("ab", "ab")
+	if err := r.Graft("team/", sub); err != nil {
+		t.Fatalf("Graft should succeed: %s", err)
+	}
+	for _, k := range []string{"team/a", "team/ab", "team", "tester"} {
+		if _, exact := r.Find(k); !exact {
+			t.Logf("%s should be found after Graft", k)
+			t.Fail()
+		}
+	}
+	n, _ := r.Find("team/ab")
+	if n.Up().Key() != "team/a" {
+		t.Logf("grafted nodes should point to their new parents")
+		t.Fail()
+	}
+
+	sub = New()
+	sub.Insert("er", "collision")
+	sub.Insert("ing", "testing")
+	if err := r.Graft("test", sub); !errors.Is(err, ErrExists) {
+		t.Logf("Graft should fail with ErrExists, got %v", err)
+		t.Fail()
+	}
+	if _, exact := r.Find("testing"); exact {
+		t.Logf("a failed Graft should not modify the tree")
+		t.Fail()
+	}
+	err := r.GraftFunc("test", sub, func(key string, old, new interface{}) interface{} { return new })
+	if err != nil {
+		t.Fatalf("GraftFunc should succeed: %s", err)
+	}
+	if n, _ := r.Find("tester"); n.Value != "collision" {
+		t.Logf("tester should be resolved to collision, is %v", n.Value)
+		t.Fail()
+	}
+}
+
+func TestGraftLimits(t *testing.T) {
+	sub := New()
+	sub.Insert("a", 1)
+	sub.Insert("b", 2)
+	sub.Insert("c", 3)
+	r := NewWithLimits(Limits{MaxKeys: 2})
+	if err := r.Graft("x/", sub); !errors.Is(err, ErrLimit) || r.Len() != 0 {
+		t.Logf("grafting 3 keys into a tree of at most 2 should fail with ErrLimit, got %v and %d keys", err, r.Len())
+		t.Fail()
+	}
+	r = NewWithLimits(Limits{MaxKeyLen: 2})
+	if err := r.Graft("xyz", sub); !errors.Is(err, ErrLimit) {
+		t.Logf("grafting keys that are too long should fail with ErrLimit, got %v", err)
+		t.Fail()
+	}
+	r = New()
+	r.SetQuota("x/", 2)
+	if err := r.Graft("x/", sub); !errors.Is(err, ErrLimit) || r.Len() != 0 {
+		t.Logf("grafting over a quota should fail, got %v and %d keys", err, r.Len())
+		t.Fail()
+	}
+	// Keys already under the prefix, so the keys are inserted one by one.
+	r = NewWithLimits(Limits{MaxKeys: 3})
+	r.Insert("x/a", 0)
+	r.Insert("x/z", 0)
+	err := r.GraftFunc("x/", sub, func(_ string, old, new interface{}) interface{} { return new })
+	if !errors.Is(err, ErrLimit) || r.Len() != 2 {
+		t.Logf("grafting over the limit should fail and leave the tree as is, got %v and %d keys", err, r.Len())
+		t.Fail()
+	}
+	// A key below the prefix, but no node for the prefix itself.
+	r = New()
+	r.Insert("x/abc", 0)
+	if err := r.Graft("x/a", sub); err != nil || r.Len() != 4 {
+		t.Logf("grafting next to x/abc should succeed, got %v and %d keys", err, r.Len())
+		t.Fail()
+	}
+	if _, exact := r.Find("x/abc"); !exact {
+		t.Logf("x/abc should still be found after the graft")
+		t.Fail()
+	}
+}
+
+func TestSetMetaLimits(t *testing.T) {
+	r := NewWithLimits(Limits{MaxNodes: 1})
+	r.Insert("abc", 1)
+	if n := r.SetMeta("abd", "tag"); n != nil {
+		t.Logf("SetMeta should return nil when the node can not be created")
+		t.Fail()
+	}
+}
@@ -0,0 +1,89 @@
+package radix
+
+// change records the value of a key before and after an Insert or Remove.
+// A nil value means the key was not present.
+type change struct {
+	key      string
+	old, new interface{}
+}
+
+// KeepHistory makes the tree r remember the last n changes made by Insert and Remove, so they
+// can be reverted with Undo and reapplied with Redo. If n is 0, no history is kept and the
+// current history is dropped. r must be the root of the radix tree.
+func (r *Radix) KeepHistory(n int) {
+	t := r.settings()
+	t.history = n
+	if n == 0 {
+		t.history = -1
+		t.undo, t.redo = nil, nil
+		return
+	}
+	if len(t.undo) > n {
+		t.undo = t.undo[len(t.undo)-n:]
+	}
+}
+
+// Undo reverts the last n changes and returns how many were reverted, this is less than n
+// when the history runs out. r must be the root of the radix tree.
+func (r *Radix) Undo(n int) int {
+	t := r.tree
+	if t == nil {
+		return 0
+	}
+	i := 0
+	for ; i < n && len(t.undo) > 0; i++ {
+		c := t.undo[len(t.undo)-1]
+		t.undo = t.undo[:len(t.undo)-1]
+		r.replay(c.key, c.old)
+		t.redo = append(t.redo, c)
+	}
+	return i
+}
+
+// Redo reapplies the last n changes reverted by Undo and returns how many were reapplied.
+// Any Insert or Remove after an Undo drops the changes that can be redone.
+// r must be the root of the radix tree.
+func (r *Radix) Redo(n int) int {
+	t := r.tree
+	if t == nil {
+		return 0
+	}
+	i := 0
+	for ; i < n && len(t.redo) > 0; i++ {
+		c := t.redo[len(t.redo)-1]
+		t.redo = t.redo[:len(t.redo)-1]
+		r.replay(c.key, c.new)
+		t.undo = append(t.undo, c)
+	}
+	return i
+}
+
+// replay sets key to value without recording it in the history.
+func (r *Radix) replay(key string, value interface{}) {
+	if t := r.tree; t != nil {
+		t.replaying = true
+		defer func() { t.replaying = false }()
+	}
+	if key == "" {
+		r.setRoot(value) // PopMin and PopMax remove the empty key
+		return
+	}
+	if value == nil {
+		r.Remove(key)
+		return
+	}
+	r.Insert(key, value)
+}
+
+// record adds a change to the history, if history is kept, and passes it to the change feed.
+func (t *tree) record(key string, old, new interface{}) {
+	t.emit(key, new)
+	if t.history < 0 || t.replaying {
+		return
+	}
+	t.undo = append(t.undo, change{key, old, new})
+	if len(t.undo) > t.history {
+		t.undo = t.undo[1:]
+	}
+	t.redo = nil
+}
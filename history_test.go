@@ -0,0 +1,55 @@
+package radix
+
+import (
+	"testing"
+)
+
+func TestUndoRedo(t *testing.T) {
+	r := New()
+	r.KeepHistory(10)
+	r.Insert("test", "a")
+	r.Insert("test", "b")
+	r.Insert("team", "c")
+	r.Remove("test")
+
+	value := func(key string) interface{} {
+		n, exact := r.Find(key)
+		if !exact {
+			return nil
+		}
+		return n.Value
+	}
+	if value("test") != nil || value("team") != "c" {
+		t.Fatalf("test should be removed and team should be c")
+	}
+	if n := r.Undo(2); n != 2 {
+		t.Logf("Undo(2) should undo 2 changes, undid %d", n)
+		t.Fail()
+	}
+	if value("test") != "b" || value("team") != nil {
+		t.Logf("after Undo(2) test should be b and team should be gone")
+		t.Fail()
+	}
+	if n := r.Undo(5); n != 2 || value("test") != nil {
+		t.Logf("Undo(5) should undo the remaining 2 changes, undid %d", n)
+		t.Fail()
+	}
+	r.Redo(3)
+	if value("test") != "b" || value("team") != "c" {
+		t.Logf("after Redo(3) test should be b and team c")
+		t.Fail()
+	}
+	r.Insert("tester", "d")
+	if r.Redo(1) != 0 {
+		t.Logf("Insert should drop the changes that can be redone")
+		t.Fail()
+	}
+
+	r.KeepHistory(1)
+	r.Insert("x", "x")
+	r.Insert("y", "y")
+	if r.Undo(2) != 1 || value("x") != "x" {
+		t.Logf("only 1 change should be kept")
+		t.Fail()
+	}
+}
@@ -0,0 +1,126 @@
+//go:build !tinygo
+
+package radix
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Handler returns an http.Handler that exposes r, values are encoded and decoded with c. The
+// URL path, without the leading slash, is the key:
+//
+//	GET /key      returns the value of key encoded with c, 404 if it is not present
+//	PUT /key      stores the request body, decoded with c, under key
+//	DELETE /key   removes key, 404 if it is not present
+//	GET /?prefix=p&limit=n&after=k
+//	              lists at most n (default 100) keys starting with p, in lexical order,
+//	              starting after key k
+//
+// With StringCodec the body is the value itself, with JSONCodec values are exchanged as JSON.
+// A list is returned as a JSON object with the keys in "keys" and, if there are more keys,
+// the key to pass as after in the next request in "next". The handler serializes all requests,
+// r must not be used in other ways while it is serving. r must be the root of the radix tree.
+func (r *Radix) Handler(c Codec) http.Handler {
+	return &handler{r: r, c: c}
+}
+
+type handler struct {
+	mu sync.Mutex
+	r  *Radix
+	c  Codec
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	key := strings.TrimPrefix(req.URL.Path, "/")
+	switch {
+	case req.Method == http.MethodGet && key == "":
+		h.list(w, req)
+	case key == "":
+		http.Error(w, "missing key", http.StatusBadRequest)
+	case req.Method == http.MethodGet:
+		n, exact := h.r.Find(key)
+		if !exact {
+			http.NotFound(w, req)
+			return
+		}
+		value, err := plain(n.Value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		b, err := h.c.Encode(value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(b)
+	case req.Method == http.MethodPut:
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		value, err := h.c.Decode(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, err := h.r.TryInsert(key, value); err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case req.Method == http.MethodDelete:
+		if _, exact := h.r.find(key); !exact {
+			http.NotFound(w, req)
+			return
+		}
+		h.r.Remove(key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "GET, PUT, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// list handles GET / with the prefix, limit and after parameters.
+func (h *handler) list(w http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+	prefix, after := q.Get("prefix"), q.Get("after")
+	limit := 100
+	if l := q.Get("limit"); l != "" {
+		var err error
+		if limit, err = strconv.Atoi(l); err != nil || limit <= 0 {
+			http.Error(w, "bad limit", http.StatusBadRequest)
+			return
+		}
+	}
+	page := struct {
+		Keys []string `json:"keys"`
+		Next string   `json:"next,omitempty"`
+	}{Keys: []string{}}
+
+	c := h.r.Cursor()
+	ok := c.Seek(prefix)
+	if after > prefix {
+		if ok = c.Seek(after); ok && c.Key() == after {
+			ok = c.Next()
+		}
+	}
+	for ; ok && strings.HasPrefix(c.Key(), prefix); ok = c.Next() {
+		if len(page.Keys) == limit {
+			page.Next = page.Keys[limit-1]
+			break
+		}
+		page.Keys = append(page.Keys, c.Key())
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
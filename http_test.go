@@ -0,0 +1,93 @@
+//go:build !tinygo
+
+package radix
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	r := New()
+	r.Insert("count", 42)
+	s := httptest.NewServer(r.Handler(StringCodec{}))
+	defer s.Close()
+
+	do := func(method, path, body string) (int, string) {
+		req, _ := http.NewRequest(method, s.URL+path, strings.NewReader(body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+
+	for _, k := range []string{"users/a", "users/b", "users/c", "groups/x"} {
+		if code, _ := do("PUT", "/"+k, "value of "+k); code != http.StatusNoContent {
+			t.Fatalf("PUT %s should succeed, got %d", k, code)
+		}
+	}
+	if code, body := do("GET", "/users/b", ""); code != http.StatusOK || body != "value of users/b" {
+		t.Logf("GET users/b should return its value, got %d %q", code, body)
+		t.Fail()
+	}
+	if code, _ := do("GET", "/count", ""); code != http.StatusInternalServerError {
+		t.Logf("GET count should fail, StringCodec can not encode 42, got %d", code)
+		t.Fail()
+	}
+	if code, body := do("GET", "/?prefix=users/&limit=2", ""); body != `{"keys":["users/a","users/b"],"next":"users/b"}`+"\n" {
+		t.Logf("the first page should hold users/a and users/b, got %d %s", code, body)
+		t.Fail()
+	}
+	if _, body := do("GET", "/?prefix=users/&limit=2&after=users/b", ""); body != `{"keys":["users/c"]}`+"\n" {
+		t.Logf("the second page should hold users/c, got %s", body)
+		t.Fail()
+	}
+	if code, _ := do("DELETE", "/users/a", ""); code != http.StatusNoContent {
+		t.Logf("DELETE users/a should succeed, got %d", code)
+		t.Fail()
+	}
+	if code, _ := do("GET", "/users/a", ""); code != http.StatusNotFound {
+		t.Logf("GET users/a should return 404 after DELETE, got %d", code)
+		t.Fail()
+	}
+	if code, _ := do("POST", "/users/a", ""); code != http.StatusMethodNotAllowed {
+		t.Logf("POST should not be allowed, got %d", code)
+		t.Fail()
+	}
+}
+
+func TestHandlerJSON(t *testing.T) {
+	r := New()
+	r.Insert("count", 42)
+	h := r.Handler(JSONCodec{})
+	do := func(method, path, body string) (int, string) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w.Code, w.Body.String()
+	}
+	if code, body := do("GET", "/count", ""); code != http.StatusOK || body != "42" {
+		t.Logf("GET count should return 42, got %d %q", code, body)
+		t.Fail()
+	}
+	if code, _ := do("PUT", "/user", `{"name":"a","age":3}`); code != http.StatusNoContent {
+		t.Fatalf("PUT user should succeed, got %d", code)
+	}
+	if n, _ := r.Find("user"); n == nil || n.Value.(map[string]interface{})["name"] != "a" {
+		t.Logf("user should be stored decoded, is %#v", n.Value)
+		t.Fail()
+	}
+	if _, body := do("GET", "/user", ""); body != `{"age":3,"name":"a"}` {
+		t.Logf("GET user should return the JSON that was put, got %s", body)
+		t.Fail()
+	}
+	if code, _ := do("PUT", "/user", `{"name":`); code != http.StatusBadRequest {
+		t.Logf("PUT of bad JSON should fail with 400, got %d", code)
+		t.Fail()
+	}
+}
@@ -0,0 +1,40 @@
+package radix
+
+// Join pairs the values of r and other by key and returns a new tree holding the results of fn.
+// fn is called in lexical order for each key in r or other, with a the value in r and b the value
+// in other; a or b is nil if the key is missing from that tree. If fn returns true, the value it
+// returns is inserted under key in the new tree. Both trees are walked once, side by side.
+// r and other must be roots of radix trees.
+func (r *Radix) Join(other *Radix, fn func(key string, a, b interface{}) (interface{}, bool)) *Radix {
+	j := New()
+	ca, cb := r.Cursor(), other.Cursor()
+	va, vb := ca.First(), cb.First()
+	for va || vb {
+		var key string
+		var a, b interface{}
+		switch {
+		case va && (!vb || ca.Key() <= cb.Key()):
+			key = ca.Key()
+		default:
+			key = cb.Key()
+		}
+		if va && ca.Key() == key {
+			a = ca.Value()
+			va = ca.Next()
+		}
+		if vb && cb.Key() == key {
+			b = cb.Value()
+			vb = cb.Next()
+		}
+		v, ok := fn(key, a, b)
+		if !ok {
+			continue
+		}
+		if key == "" {
+			j.set(v)
+			continue
+		}
+		j.Insert(key, v)
+	}
+	return j
+}
@@ -0,0 +1,46 @@
+package radix
+
+import (
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	routes := New()
+	routes.Insert("/api", "backend")
+	routes.Insert("/static", "cdn")
+	meta := New()
+	meta.Insert("/api", "auth")
+	meta.Insert("/health", "public")
+
+	got := ""
+	inner := routes.Join(meta, func(key string, a, b interface{}) (interface{}, bool) {
+		got += key + " "
+		if a == nil || b == nil {
+			return nil, false
+		}
+		return a.(string) + "+" + b.(string), true
+	})
+	if got != "/api /health /static " {
+		t.Logf("Join should call fn for /api /health /static, called it for %s", got)
+		t.Fail()
+	}
+	if inner.Len() != 1 {
+		t.Fatalf("inner join should hold 1 key, holds %d", inner.Len())
+	}
+	if n, exact := inner.Find("/api"); !exact || n.Value != "backend+auth" {
+		t.Logf("/api should be joined to backend+auth")
+		t.Fail()
+	}
+
+	outer := routes.Join(meta, func(key string, a, b interface{}) (interface{}, bool) {
+		return [2]interface{}{a, b}, true
+	})
+	if n, exact := outer.Find("/health"); !exact || n.Value != [2]interface{}{nil, "public"} {
+		t.Logf("/health should be in the outer join with only the right value")
+		t.Fail()
+	}
+	if outer.Len() != 3 {
+		t.Logf("outer join should hold 3 keys, holds %d", outer.Len())
+		t.Fail()
+	}
+}
@@ -0,0 +1,83 @@
+//go:build !tinygo
+
+package radix
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// MarshalNestedJSON returns the keys and values of r as nested JSON objects: each key is split
+// on sep and every part but the last becomes an object holding the next. For example the keys
+// "a/b" and "a/c" with sep "/" become {"a":{"b":...,"c":...}}. When a key is also a prefix of
+// other keys, as "a" is for "a/b", its value is stored under the empty name in the object of
+// "a". A key ending in sep needs that same name: "a" and "a/" can not both be stored, and an
+// error wrapping ErrExists is returned. Values are encoded with encoding/json.
+func (r *Radix) MarshalNestedJSON(sep string) ([]byte, error) {
+	root := make(object)
+	var err error
+	r.ordered("", func(key string, n *Radix) bool {
+		parts := []string{key}
+		if sep != "" {
+			parts = strings.Split(key, sep)
+		}
+		m := root
+		for _, p := range parts[:len(parts)-1] {
+			switch x := m[p].(type) {
+			case object:
+				m = x
+			case nil:
+				sub := make(object)
+				m[p] = sub
+				m = sub
+			default:
+				// a key with a value, move the value into a new object
+				sub := object{"": x}
+				m[p] = sub
+				m = sub
+			}
+		}
+		p := parts[len(parts)-1]
+		if sub, ok := m[p].(object); ok {
+			m, p = sub, ""
+		}
+		if _, ok := m[p]; ok {
+			err = wrap(ErrExists, key+" and the key without the trailing "+sep+" have the same name in nested JSON")
+			return false
+		}
+		m[p] = n.Value
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(root)
+}
+
+// object is a JSON object built by MarshalNestedJSON. It has its own type, so a value that is
+// a map[string]interface{} is not mistaken for one.
+type object map[string]interface{}
+
+// JSONCodec is a Codec that encodes values with encoding/json. Values are decoded as
+// json.Unmarshal decodes into an interface{}: numbers become float64s, objects
+// map[string]interface{}s.
+type JSONCodec struct{}
+
+// Encode implements Codec.
+func (JSONCodec) Encode(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+// Decode implements Codec.
+func (JSONCodec) Decode(b []byte) (interface{}, error) {
+	var v interface{}
+	err := json.Unmarshal(b, &v)
+	return v, err
+}
+
+// MarshalJSON implements json.Marshaler, the uncompressed value is encoded.
+func (v *Compressed) MarshalJSON() ([]byte, error) {
+	x, err := v.Value()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(x)
+}
@@ -0,0 +1,51 @@
+//go:build !tinygo
+
+package radix
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMarshalNestedJSON(t *testing.T) {
+	r := New()
+	r.Insert("server/port", 80)
+	r.Insert("server/host", "localhost")
+	r.Insert("log", "info")
+	r.Insert("log/file", "/var/log/x")
+	r.Insert("db/main/user", "admin")
+	b, err := r.MarshalNestedJSON("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"db":{"main":{"user":"admin"}},"log":{"":"info","file":"/var/log/x"},"server":{"host":"localhost","port":80}}`
+	if string(b) != want {
+		t.Logf("MarshalNestedJSON should be\n%s, is\n%s", want, b)
+		t.Fail()
+	}
+	b, _ = New().MarshalNestedJSON("/")
+	if string(b) != "{}" {
+		t.Logf("an empty tree should be {}, is %s", b)
+		t.Fail()
+	}
+}
+
+func TestMarshalNestedJSONCollision(t *testing.T) {
+	r := New()
+	r.Insert("a", 1)
+	r.Insert("a/", 2)
+	if _, err := r.MarshalNestedJSON("/"); !errors.Is(err, ErrExists) {
+		t.Logf("a and a/ should collide with ErrExists, got %v", err)
+		t.Fail()
+	}
+	r.Remove("a")
+	r.Insert("a/b", map[string]interface{}{"x": 3})
+	b, err := r.MarshalNestedJSON("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"a":{"":2,"b":{"x":3}}}`; string(b) != want {
+		t.Logf("MarshalNestedJSON should be %s, is %s", want, b)
+		t.Fail()
+	}
+}
@@ -0,0 +1,113 @@
+// Package keyenc encodes composite keys for package radix. The fields of a key are appended
+// to a byte slice in an encoding that keeps their order: comparing two encoded keys as strings
+// gives the same result as comparing their fields one by one. Fields are not tagged with their
+// type, the decoder must know the layout of the key:
+//
+//	k := keyenc.AppendString(nil, "user")
+//	k = keyenc.AppendUint64(k, 42)
+//	k = keyenc.AppendTime(k, time.Now())
+//	r.Insert(string(k), v)
+//
+//	name, rest, err := keyenc.String(k)
+//	id, rest, err := keyenc.Uint64(rest)
+//	when, rest, err := keyenc.Time(rest)
+package keyenc
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrFormat is returned when a key can not be decoded.
+var ErrFormat = errors.New("keyenc: bad key")
+
+// Strings are terminated by 0x00 0x01, a 0x00 in the string is escaped as 0x00 0xff. The
+// terminator sorts before any escaped or other byte, so a string sorts before its extensions.
+const (
+	escape     = 0x00
+	terminator = 0x01
+	escaped    = 0xff
+)
+
+// AppendString appends the encoding of s to b.
+func AppendString(b []byte, s string) []byte {
+	for {
+		i := strings.IndexByte(s, escape)
+		if i < 0 {
+			break
+		}
+		b = append(b, s[:i]...)
+		b = append(b, escape, escaped)
+		s = s[i+1:]
+	}
+	b = append(b, s...)
+	return append(b, escape, terminator)
+}
+
+// String decodes a string from the start of b and returns it with the rest of b.
+func String(b []byte) (string, []byte, error) {
+	var sb strings.Builder
+	for i := 0; i < len(b); i++ {
+		if b[i] != escape {
+			sb.WriteByte(b[i])
+			continue
+		}
+		if i+1 == len(b) {
+			break
+		}
+		switch b[i+1] {
+		case terminator:
+			return sb.String(), b[i+2:], nil
+		case escaped:
+			sb.WriteByte(escape)
+			i++
+		default:
+			return "", b, ErrFormat
+		}
+	}
+	return "", b, ErrFormat
+}
+
+// AppendUint64 appends the encoding of v to b, 8 bytes big endian.
+func AppendUint64(b []byte, v uint64) []byte {
+	return binary.BigEndian.AppendUint64(b, v)
+}
+
+// Uint64 decodes an uint64 from the start of b and returns it with the rest of b.
+func Uint64(b []byte) (uint64, []byte, error) {
+	if len(b) < 8 {
+		return 0, b, ErrFormat
+	}
+	return binary.BigEndian.Uint64(b), b[8:], nil
+}
+
+// AppendInt64 appends the encoding of v to b, 8 bytes big endian with the sign bit flipped so
+// negative numbers sort before positive ones.
+func AppendInt64(b []byte, v int64) []byte {
+	return AppendUint64(b, uint64(v)^1<<63)
+}
+
+// Int64 decodes an int64 from the start of b and returns it with the rest of b.
+func Int64(b []byte) (int64, []byte, error) {
+	v, rest, err := Uint64(b)
+	return int64(v ^ 1<<63), rest, err
+}
+
+// AppendTime appends the encoding of t to b, the seconds since the Unix epoch as with AppendInt64
+// followed by the nanoseconds in 4 bytes. The location of t is not encoded.
+func AppendTime(b []byte, t time.Time) []byte {
+	b = AppendInt64(b, t.Unix())
+	return binary.BigEndian.AppendUint32(b, uint32(t.Nanosecond()))
+}
+
+// Time decodes a time from the start of b and returns it, in UTC, with the rest of b.
+func Time(b []byte) (time.Time, []byte, error) {
+	if len(b) < 12 {
+		return time.Time{}, b, ErrFormat
+	}
+	sec, rest, _ := Int64(b)
+	nsec := binary.BigEndian.Uint32(rest)
+	return time.Unix(sec, int64(nsec)).UTC(), rest[4:], nil
+}
@@ -0,0 +1,79 @@
+package keyenc
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+type tuple struct {
+	s string
+	n int64
+	t time.Time
+}
+
+func (a tuple) less(b tuple) bool {
+	if a.s != b.s {
+		return a.s < b.s
+	}
+	if a.n != b.n {
+		return a.n < b.n
+	}
+	return a.t.Before(b.t)
+}
+
+func (a tuple) encode() string {
+	k := AppendString(nil, a.s)
+	k = AppendInt64(k, a.n)
+	return string(AppendTime(k, a.t))
+}
+
+func TestOrder(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tuples := []tuple{
+		{"a", 1, t0}, {"a", -1, t0}, {"a\x00", 0, t0}, {"a\x00b", 0, t0}, {"ab", 0, t0}, {"", 5, t0},
+		{"a", 1, t0.Add(time.Nanosecond)}, {"a", 1, t0.Add(-time.Hour)}, {"b", -1 << 63, t0}, {"a", 1<<63 - 1, t0},
+	}
+	byTuple := append([]tuple(nil), tuples...)
+	sort.Slice(byTuple, func(i, j int) bool { return byTuple[i].less(byTuple[j]) })
+	byKey := append([]tuple(nil), tuples...)
+	sort.Slice(byKey, func(i, j int) bool { return byKey[i].encode() < byKey[j].encode() })
+	for i := range byTuple {
+		if byTuple[i] != byKey[i] {
+			t.Fatalf("position %d: tuple order has %v, key order has %v", i, byTuple[i], byKey[i])
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	when := time.Date(2024, 5, 6, 7, 8, 9, 10, time.UTC)
+	k := AppendString(nil, "us\x00er")
+	k = AppendUint64(k, 42)
+	k = AppendInt64(k, -7)
+	k = AppendTime(k, when)
+
+	s, rest, err := String(k)
+	if err != nil || s != "us\x00er" {
+		t.Fatalf("string should decode to %q, got %q: %v", "us\x00er", s, err)
+	}
+	u, rest, err := Uint64(rest)
+	if err != nil || u != 42 {
+		t.Fatalf("uint64 should decode to 42, got %d: %v", u, err)
+	}
+	i, rest, err := Int64(rest)
+	if err != nil || i != -7 {
+		t.Fatalf("int64 should decode to -7, got %d: %v", i, err)
+	}
+	tm, rest, err := Time(rest)
+	if err != nil || !tm.Equal(when) || len(rest) != 0 {
+		t.Fatalf("time should decode to %s, got %s: %v", when, tm, err)
+	}
+	if _, _, err := String([]byte("unterminated")); err != ErrFormat {
+		t.Logf("unterminated string should fail with ErrFormat, got %v", err)
+		t.Fail()
+	}
+	if _, _, err := Time(k[:3]); err != ErrFormat {
+		t.Logf("short time should fail with ErrFormat, got %v", err)
+		t.Fail()
+	}
+}
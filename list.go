@@ -0,0 +1,35 @@
+package radix
+
+import (
+	"strings"
+)
+
+// List returns the keys directly under prefix, S3 style: keys starting with prefix
+// that do not contain delim after the prefix are returned in keys. Keys that do contain it are
+// rolled up into one entry in prefixes, that holds the key up to and including the first
+// delim after prefix. Both slices are sorted. If delim is empty, all keys starting with prefix
+// are returned.
+func (r *Radix) List(prefix, delim string) (keys []string, prefixes []string) {
+	n := r.under(prefix)
+	if n == nil {
+		return nil, nil
+	}
+	start := n.Key()[len(r.Key()):]
+	n.ordered(start, func(key string, _ *Radix) bool {
+		if delim == "" {
+			keys = append(keys, key)
+			return true
+		}
+		i := strings.Index(key[len(prefix):], delim)
+		if i < 0 {
+			keys = append(keys, key)
+			return true
+		}
+		p := key[:len(prefix)+i+len(delim)]
+		if len(prefixes) == 0 || prefixes[len(prefixes)-1] != p {
+			prefixes = append(prefixes, p)
+		}
+		return true
+	})
+	return keys, prefixes
+}
@@ -0,0 +1,35 @@
+package radix
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestList(t *testing.T) {
+	r := New()
+	for _, k := range []string{"photos/2012/a.jpg", "photos/2012/b.jpg", "photos/2013/c.jpg", "photos/index", "photos/", "music/x.mp3"} {
+		r.Insert(k, k)
+	}
+	keys, prefixes := r.List("photos/", "/")
+	if fmt.Sprint(keys) != "[photos/ photos/index]" {
+		t.Logf("keys should be [photos/ photos/index], are %v", keys)
+		t.Fail()
+	}
+	if fmt.Sprint(prefixes) != "[photos/2012/ photos/2013/]" {
+		t.Logf("prefixes should be [photos/2012/ photos/2013/], are %v", prefixes)
+		t.Fail()
+	}
+	keys, prefixes = r.List("", "/")
+	if len(keys) != 0 || fmt.Sprint(prefixes) != "[music/ photos/]" {
+		t.Logf("List of the root should be [music/ photos/], is %v %v", keys, prefixes)
+		t.Fail()
+	}
+	if keys, _ := r.List("photos/2012/", ""); len(keys) != 2 {
+		t.Logf("without delimiter all keys under photos/2012/ should be listed, got %v", keys)
+		t.Fail()
+	}
+	if keys, prefixes := r.List("video/", "/"); keys != nil || prefixes != nil {
+		t.Logf("nothing should be listed under video/")
+		t.Fail()
+	}
+}
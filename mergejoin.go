@@ -0,0 +1,84 @@
+package radix
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrUnsorted is returned by MergeJoin when a stream is not in ascending order.
+var ErrUnsorted = errors.New("radix: stream not sorted")
+
+// Stream is a stream of keys in ascending lexical order, such as the lines of a sorted file.
+// Next returns the next key, or io.EOF at the end of the stream.
+type Stream interface {
+	Next() (string, error)
+}
+
+// MergeJoin walks the keys of r and of the streams together in lexical order, without loading
+// the streams into memory. f is called once for each key found in r or in any of the streams,
+// with the node of the key in r, or nil if r does not hold the key, and in, where in[i] tells
+// if stream i holds the key. With a single stream, a node and in[0] mean the key is found on
+// both sides, a node alone that it is only in r and in[0] alone that it is only in the stream.
+// in is reused between calls. Duplicate keys in a stream are reported once.
+//
+// If f returns an error the join stops and that error is returned, as is any error other than
+// io.EOF returned by a stream. If a stream is not sorted, an error wrapping ErrUnsorted is
+// returned. f must not modify the tree. r must be the root of the radix tree.
+func (r *Radix) MergeJoin(f func(key string, n *Radix, in []bool) error, streams ...Stream) error {
+	c := r.Cursor()
+	valid := c.First()
+	heads := make([]string, len(streams))
+	live := make([]bool, len(streams))
+	next := func(i int) error {
+		key, err := streams[i].Next()
+		if err == io.EOF {
+			live[i] = false
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if live[i] && key < heads[i] {
+			return wrap(ErrUnsorted, key+" after "+heads[i])
+		}
+		heads[i], live[i] = key, true
+		return nil
+	}
+	for i := range streams {
+		if err := next(i); err != nil {
+			return err
+		}
+	}
+	in := make([]bool, len(streams))
+	for {
+		key, found := "", false
+		if valid {
+			key, found = c.Key(), true
+		}
+		for i := range streams {
+			if live[i] && (!found || heads[i] < key) {
+				key, found = heads[i], true
+			}
+		}
+		if !found {
+			return nil
+		}
+		var n *Radix
+		if valid && c.Key() == key {
+			n = c.Node()
+			valid = c.Next()
+		}
+		for i := range streams {
+			in[i] = false
+			for live[i] && heads[i] == key {
+				in[i] = true
+				if err := next(i); err != nil {
+					return err
+				}
+			}
+		}
+		if err := f(key, n, in); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package radix
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+type sliceStream []string
+
+func (s *sliceStream) Next() (string, error) {
+	if len(*s) == 0 {
+		return "", io.EOF
+	}
+	key := (*s)[0]
+	*s = (*s)[1:]
+	return key, nil
+}
+
+func TestMergeJoin(t *testing.T) {
+	r := New()
+	for _, k := range []string{"apple", "banana", "cherry"} {
+		r.Insert(k, k)
+	}
+	left := &sliceStream{"apple", "apricot", "cherry", "cherry", "date"}
+	right := &sliceStream{"banana", "date"}
+	got := ""
+	err := r.MergeJoin(func(key string, n *Radix, in []bool) error {
+		got += key + ":"
+		if n != nil {
+			got += "r"
+		}
+		for i, ok := range in {
+			if ok {
+				got += string(rune('0' + i))
+			}
+		}
+		got += " "
+		return nil
+	}, left, right)
+	want := "apple:r0 apricot:0 banana:r1 cherry:r0 date:01 "
+	if err != nil || got != want {
+		t.Logf("MergeJoin should report %q, reported %q, error %v", want, got, err)
+		t.Fail()
+	}
+
+	unsorted := &sliceStream{"b", "a"}
+	err = r.MergeJoin(func(string, *Radix, []bool) error { return nil }, unsorted)
+	if !errors.Is(err, ErrUnsorted) {
+		t.Logf("unsorted stream should fail with ErrUnsorted, got %v", err)
+		t.Fail()
+	}
+}
@@ -0,0 +1,81 @@
+package radix
+
+// TrackModifications makes the tree r record, for each node, the revision of the tree at the
+// last change to its Value, see Modified and ModifiedSince. r must be the root of the radix tree.
+func (r *Radix) TrackModifications() {
+	t := r.settings()
+	if !t.modifications {
+		t.modifications = true
+		t.tracked = t.rev
+	}
+}
+
+// Modified returns the revision of the tree at the last change to the Value of r, see Revision.
+// It returns 0 if r has not been changed since modifications are tracked.
+func (r *Radix) Modified() uint64 {
+	if r.ext == nil {
+		return 0
+	}
+	return r.ext.rev
+}
+
+// ModifiedSince returns the nodes with a non-nil Value that have been changed after revision
+// rev, in lexical order.
+func (r *Radix) ModifiedSince(rev uint64) []*Radix {
+	var nodes []*Radix
+	r.WalkModifiedSince(rev, func(n *Radix) bool {
+		nodes = append(nodes, n)
+		return true
+	})
+	return nodes
+}
+
+// WalkModifiedSince calls f for each node with a non-nil Value that has been changed after
+// revision rev, in lexical order. Subtrees without such nodes are skipped. If f returns false
+// the walk stops.
+func (r *Radix) WalkModifiedSince(rev uint64, f func(n *Radix) bool) {
+	r.modifiedSince(rev, f)
+}
+
+func (r *Radix) modifiedSince(rev uint64, f func(n *Radix) bool) bool {
+	if r.ext == nil || r.ext.maxRev <= rev {
+		return true
+	}
+	if r.Value != nil && r.ext.rev > rev && !f(r) {
+		return false
+	}
+	for _, k := range sortedChildren(r.children) {
+		if !r.children[k].modifiedSince(rev, f) {
+			return false
+		}
+	}
+	return true
+}
+
+// modified stamps n with the current revision, if modifications are tracked.
+func (t *tree) modified(n *Radix) {
+	if !t.modifications || n == nil {
+		return
+	}
+	n.extra().rev = t.rev
+	for ; n != nil && n.extra().maxRev < t.rev; n = n.parent {
+		n.ext.maxRev = t.rev
+	}
+}
+
+// stamp stamps all nodes in the subtree n with the current revision, if modifications are tracked.
+func (t *tree) stamp(n *Radix) {
+	if !t.modifications {
+		return
+	}
+	var stamp func(*Radix)
+	stamp = func(n *Radix) {
+		ext := n.extra()
+		ext.rev, ext.maxRev = t.rev, t.rev
+		for _, child := range n.children {
+			stamp(child)
+		}
+	}
+	stamp(n)
+	t.modified(n)
+}
@@ -0,0 +1,51 @@
+package radix
+
+import (
+	"testing"
+)
+
+func TestModifiedSince(t *testing.T) {
+	r := New()
+	r.TrackModifications()
+	r.Insert("test", "a")
+	r.Insert("team", "b")
+	rev := r.Revision()
+	r.Insert("tester", "c")
+	r.Insert("test", "d")
+	r.Insert("toast", "e")
+
+	got := ""
+	for _, n := range r.ModifiedSince(rev) {
+		got += n.Key() + " "
+	}
+	if got != "test tester toast " {
+		t.Logf("modified since %d should be test tester toast, is %s", rev, got)
+		t.Fail()
+	}
+	if n, _ := r.Find("team"); n.Modified() != rev {
+		t.Logf("team should be modified at revision %d, is %d", rev, n.Modified())
+		t.Fail()
+	}
+	if len(r.ModifiedSince(r.Revision())) != 0 {
+		t.Logf("nothing should be modified since the current revision")
+		t.Fail()
+	}
+	visited := 0
+	r.WalkModifiedSince(0, func(n *Radix) bool {
+		visited++
+		return visited < 2
+	})
+	if visited != 2 {
+		t.Logf("the walk should stop when f returns false")
+		t.Fail()
+	}
+
+	sub := New()
+	sub.Insert("x", "x")
+	rev = r.Revision()
+	r.Graft("grafted/", sub)
+	if m := r.ModifiedSince(rev); len(m) != 1 || m[0].Key() != "grafted/x" {
+		t.Logf("grafted keys should be modified")
+		t.Fail()
+	}
+}
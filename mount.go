@@ -0,0 +1,59 @@
+package radix
+
+type mount struct {
+	root   *Radix
+	prefix string
+}
+
+// Mount makes the keys starting with prefix in target visible in r under alias: Find of
+// alias+key finds prefix+key in target. Nothing is copied, changes to target are seen through
+// the mount. target may be r itself, to expose a subtree under a second prefix. Keys under a
+// mounted alias are only looked up in target, even when r holds them, and a node of target
+// outside prefix is never returned. When mounts overlap the longest alias is used. Mounts are
+// not followed recursively, and only Find and the functions built on it look at them.
+// r must be the root of the radix tree.
+func (r *Radix) Mount(alias string, target *Radix, prefix string) {
+	t := r.settings()
+	if t.mounts == nil {
+		t.mounts = New()
+	}
+	m := &mount{root: target, prefix: prefix}
+	if alias == "" {
+		t.mounts.set(m)
+		return
+	}
+	t.mounts.Insert(alias, m)
+}
+
+// Unmount removes the mount at alias, see Mount. r must be the root of the radix tree.
+func (r *Radix) Unmount(alias string) {
+	t := r.tree
+	if t == nil || t.mounts == nil {
+		return
+	}
+	if alias == "" {
+		t.mounts.set(nil)
+		return
+	}
+	t.mounts.Remove(alias)
+}
+
+// mounted returns the mount with the longest alias that is a prefix of key, and the rest of key.
+func (t *tree) mounted(key string) (*mount, string) {
+	var m *mount
+	rest := ""
+	t.mounts.prefixesOf(key, func(alias string, v interface{}) {
+		m, rest = v.(*mount), key[len(alias):]
+	})
+	return m, rest
+}
+
+// find finds prefix+key in the target of m, see Radix.Find.
+func (m *mount) find(key string) (node *Radix, exact bool) {
+	node, exact = m.root.find(m.prefix + key)
+	if node == nil || len(node.Key()) < len(m.root.Key())+len(m.prefix) {
+		return nil, false
+	}
+	m.root.tree.hit(node)
+	return node, exact
+}
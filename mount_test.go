@@ -0,0 +1,44 @@
+package radix
+
+import (
+	"testing"
+)
+
+func TestMount(t *testing.T) {
+	r := New()
+	r.Insert("v2/users/alice", 1)
+	r.Insert("v2/users/bob", 2)
+	r.Insert("v1/old", 3)
+	r.Mount("v1/", r, "v2/")
+	if n, exact := r.Find("v1/users/alice"); !exact || n.Value != 1 {
+		t.Logf("v1/users/alice should find v2/users/alice")
+		t.Fail()
+	}
+	if _, exact := r.Find("v1/old"); exact {
+		t.Logf("v1/old should be hidden by the mount")
+		t.Fail()
+	}
+	if n, _ := r.Find("v1/nothing"); n != nil {
+		t.Logf("v1/nothing should not return a node outside the mount, got %s", n.Key())
+		t.Fail()
+	}
+	r.Insert("v2/users/carol", 4)
+	if n, exact := r.Find("v1/users/carol"); !exact || n.Value != 4 {
+		t.Logf("keys inserted after mounting should be visible through the mount")
+		t.Fail()
+	}
+
+	other := New()
+	other.Insert("x", 5)
+	r.Mount("ext/", other, "")
+	if n, exact := r.Find("ext/x"); !exact || n.Value != 5 {
+		t.Logf("ext/x should find x in the other tree")
+		t.Fail()
+	}
+
+	r.Unmount("v1/")
+	if n, exact := r.Find("v1/old"); !exact || n.Value != 3 {
+		t.Logf("v1/old should be found after Unmount")
+		t.Fail()
+	}
+}
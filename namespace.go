@@ -0,0 +1,86 @@
+package radix
+
+// Namespace is a view on the part of a tree under a prefix. All keys given to
+// and returned by its methods are relative to that prefix.
+type Namespace struct {
+	root   *Radix
+	prefix string
+}
+
+// Namespace returns a view on the keys starting with prefix. r must be the root of the radix tree.
+func (r *Radix) Namespace(prefix string) *Namespace {
+	return &Namespace{root: r, prefix: prefix}
+}
+
+// Prefix returns the prefix of the namespace.
+func (ns *Namespace) Prefix() string { return ns.prefix }
+
+// Insert inserts value under prefix+key, see Radix.Insert.
+func (ns *Namespace) Insert(key string, value interface{}) *Radix {
+	return ns.root.Insert(ns.prefix+key, value)
+}
+
+// Find finds prefix+key, see Radix.Find. Nodes outside the namespace are never returned.
+func (ns *Namespace) Find(key string) (node *Radix, exact bool) {
+	node, exact = ns.root.Find(ns.prefix + key)
+	if node == nil || len(node.Key()) < len(ns.prefix) {
+		return nil, false
+	}
+	return node, exact
+}
+
+// Remove removes prefix+key, see Radix.Remove.
+func (ns *Namespace) Remove(key string) *Radix {
+	return ns.root.Remove(ns.prefix + key)
+}
+
+// Len returns the number of keys in the namespace.
+func (ns *Namespace) Len() int {
+	n := ns.root.under(ns.prefix)
+	if n == nil {
+		return 0
+	}
+	return n.Len()
+}
+
+// Keys returns the keys in the namespace, without the prefix, in lexical order.
+func (ns *Namespace) Keys() []string {
+	keys := []string{}
+	n := ns.root.under(ns.prefix)
+	if n == nil {
+		return keys
+	}
+	n.ordered(n.Key()[len(ns.prefix):], func(key string, _ *Radix) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// Clear removes all keys in the namespace and returns how many were removed. The subtree holding
+// them is cut off at once; for history, tombstones and the change feed each key counts as
+// removed by Remove, so Undo restores them.
+func (ns *Namespace) Clear() int {
+	n := ns.root.under(ns.prefix)
+	if n == nil {
+		return 0
+	}
+	l := n.Len()
+	t := ns.root.tree
+	if t == nil || t.bare {
+		n.cut()
+		return l
+	}
+	if l > 0 {
+		t.rev++
+	}
+	nodes := n.detached(n.Key(), t.forget)
+	if n == ns.root {
+		nodes-- // the root stays
+	}
+	nodes += n.cut()
+	t.keys -= l
+	t.nodes -= nodes
+	t.epoch++
+	return l
+}
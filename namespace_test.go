@@ -0,0 +1,70 @@
+package radix
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestNamespace(t *testing.T) {
+	r := New()
+	r.Insert("tenant", "root")
+	a := r.Namespace("tenant/a/")
+	b := r.Namespace("tenant/b/")
+	a.Insert("x", 1)
+	a.Insert("y", 2)
+	b.Insert("x", 3)
+	if a.Len() != 2 || b.Len() != 1 {
+		t.Logf("namespaces should hold 2 and 1 keys, hold %d and %d", a.Len(), b.Len())
+		t.Fail()
+	}
+	if fmt.Sprint(a.Keys()) != "[x y]" {
+		t.Logf("keys of a should be [x y], are %v", a.Keys())
+		t.Fail()
+	}
+	if n, exact := b.Find("x"); !exact || n.Value != 3 {
+		t.Logf("x should be found in b")
+		t.Fail()
+	}
+	// tenant is stored outside the namespace.
+	if n, _ := b.Find("z"); n != nil {
+		t.Logf("Find should not return nodes outside the namespace, got %s", n.Key())
+		t.Fail()
+	}
+	if c := a.Clear(); c != 2 {
+		t.Logf("Clear should remove 2 keys, removed %d", c)
+		t.Fail()
+	}
+	if r.Len() != 2 || a.Len() != 0 {
+		t.Logf("after Clear only tenant and tenant/b/x should be left, Len is %d", r.Len())
+		t.Fail()
+	}
+	if n, exact := r.Find("tenant/b/x"); !exact || n.Value != 3 {
+		t.Logf("tenant/b/x should still be found")
+		t.Fail()
+	}
+}
+
+func TestNamespaceClearHistory(t *testing.T) {
+	for _, prefix := range []string{"tenant/a/", "tenant/", ""} {
+		r := New()
+		r.KeepHistory(10)
+		r.KeepTombstones(time.Hour)
+		for _, k := range []string{"tenant/a/x", "tenant/a/y", "tenant/b/x", "other"} {
+			r.Insert(k, k)
+		}
+		before := fmt.Sprint(keysOf(r))
+		c := r.Namespace(prefix).Clear()
+		if len(r.Tombstones(0)) != c {
+			t.Logf("%q: Clear should leave a tombstone for each of the %d keys, left %d", prefix, c, len(r.Tombstones(0)))
+			t.Fail()
+		}
+		checkCounts(t, r, "Clear "+prefix)
+		checkSizes(t, r, "Clear "+prefix)
+		if n := r.Undo(c); n != c || fmt.Sprint(keysOf(r)) != before {
+			t.Logf("%q: Undo should restore the %d keys cleared, restored %d: %v", prefix, c, n, keysOf(r))
+			t.Fail()
+		}
+		checkCounts(t, r, "Undo "+prefix)
+	}
+}
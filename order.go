@@ -0,0 +1,76 @@
+package radix
+
+import (
+	"container/list"
+)
+
+// KeepInsertionOrder makes the tree r remember the order in which keys are inserted, see
+// InsertionDo. Overwriting the value of a key does not change its position, removing and
+// inserting it again does. Keys already in r are ordered lexically. r must be the root of
+// the radix tree.
+func (r *Radix) KeepInsertionOrder() {
+	t := r.settings()
+	if t.order != nil {
+		return
+	}
+	t.order = list.New()
+	t.elements = make(map[string]*list.Element)
+	r.reorder()
+}
+
+// InsertionDo calls f for each key and its value in r, in the order the keys were inserted.
+// If the insertion order is not kept, f is never called. f must not modify the tree.
+// r must be the root of the radix tree.
+func (r *Radix) InsertionDo(f func(key string, value interface{})) {
+	if r.tree == nil || r.tree.order == nil {
+		return
+	}
+	for e := r.tree.order.Front(); e != nil; e = e.Next() {
+		key := e.Value.(string)
+		if n, exact := r.find(key); exact {
+			f(key, n.Uncompressed())
+		}
+	}
+}
+
+// added appends key to the insertion order, if it is kept.
+func (t *tree) added(key string) {
+	if t.order == nil {
+		return
+	}
+	if _, ok := t.elements[key]; !ok {
+		t.elements[key] = t.order.PushBack(key)
+	}
+}
+
+// dropped removes key from the insertion order, if it is kept.
+func (t *tree) dropped(key string) {
+	if t.order == nil {
+		return
+	}
+	if e, ok := t.elements[key]; ok {
+		t.order.Remove(e)
+		delete(t.elements, key)
+	}
+}
+
+// reorder brings the insertion order in line with the keys in r, after the structure of
+// r has been changed directly: keys that are gone are dropped and new keys are appended in
+// lexical order.
+func (r *Radix) reorder() {
+	t := r.tree
+	if t == nil || t.order == nil {
+		return
+	}
+	present := make(map[string]bool)
+	r.ordered("", func(key string, _ *Radix) bool {
+		present[key] = true
+		t.added(key)
+		return true
+	})
+	for key := range t.elements {
+		if !present[key] {
+			t.dropped(key)
+		}
+	}
+}
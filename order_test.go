@@ -0,0 +1,40 @@
+package radix
+
+import (
+	"testing"
+)
+
+func TestInsertionOrder(t *testing.T) {
+	r := New()
+	r.Insert("b", 0)
+	r.KeepInsertionOrder()
+	r.Insert("z", 1)
+	r.Insert("a", 2)
+	r.Insert("m", 3)
+	r.Insert("z", 4) // keeps its place
+	r.Remove("a")
+	r.Insert("a", 5)
+
+	got := ""
+	r.InsertionDo(func(key string, value interface{}) {
+		got += key
+	})
+	if got != "bzma" {
+		t.Logf("insertion order should be bzma, is %s", got)
+		t.Fail()
+	}
+
+	r.Namespace("m").Clear()
+	sub := New()
+	sub.Insert("y", 6)
+	sub.Insert("x", 7)
+	r.Graft("q", sub)
+	got = ""
+	r.InsertionDo(func(key string, value interface{}) {
+		got += key + " "
+	})
+	if got != "b z a qx qy " {
+		t.Logf("insertion order should be b z a qx qy, is %s", got)
+		t.Fail()
+	}
+}
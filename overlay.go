@@ -0,0 +1,77 @@
+package radix
+
+import (
+	"sort"
+)
+
+// Overlay is a read-only view on several trees, the layers. A key present in more than one
+// layer is taken from the first of those layers.
+type Overlay struct {
+	layers []*Radix
+}
+
+// NewOverlay returns an overlay of layers, the first layer takes precedence. Each layer
+// must be the root of a radix tree.
+func NewOverlay(layers ...*Radix) *Overlay {
+	return &Overlay{layers: layers}
+}
+
+// Find looks key up in the layers. The node of the first layer that holds key is returned
+// with exact set to true. If no layer holds key, the node with the longest key that is a
+// prefix of key is returned with exact set to false, see Radix.Find.
+func (o *Overlay) Find(key string) (node *Radix, exact bool) {
+	longest := -1
+	for _, l := range o.layers {
+		n, exact := l.Find(key)
+		if exact {
+			return n, true
+		}
+		if n == nil {
+			continue
+		}
+		if k := len(n.Key()); k > longest {
+			node, longest = n, k
+		}
+	}
+	return node, false
+}
+
+// Prefix returns the nodes whose keys start with prefix in any of the layers, in lexical order.
+func (o *Overlay) Prefix(prefix string) []*Radix {
+	seen := make(map[string]bool)
+	var nodes []*Radix
+	var keys []string
+	for _, l := range o.layers {
+		for _, n := range l.Prefix(prefix) {
+			k := n.Key()
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			nodes = append(nodes, n)
+			keys = append(keys, k)
+		}
+	}
+	sort.Sort(byKey{keys, nodes})
+	return nodes
+}
+
+// Do calls f for the Value of each key in the overlay, in lexical order.
+func (o *Overlay) Do(f func(interface{})) {
+	for _, n := range o.Prefix("") {
+		f(n.Uncompressed())
+	}
+}
+
+// byKey sorts nodes by their keys.
+type byKey struct {
+	keys  []string
+	nodes []*Radix
+}
+
+func (b byKey) Len() int           { return len(b.keys) }
+func (b byKey) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+func (b byKey) Swap(i, j int) {
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+	b.nodes[i], b.nodes[j] = b.nodes[j], b.nodes[i]
+}
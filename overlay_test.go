@@ -0,0 +1,39 @@
+package radix
+
+import (
+	"testing"
+)
+
+func TestOverlay(t *testing.T) {
+	defaults := New()
+	defaults.Insert("color", "blue")
+	defaults.Insert("size", "m")
+	defaults.Insert("log", "info")
+	env := New()
+	env.Insert("size", "l")
+	env.Insert("log/level", "debug")
+	o := NewOverlay(env, defaults)
+
+	if n, exact := o.Find("size"); !exact || n.Value != "l" {
+		t.Logf("size should be taken from env")
+		t.Fail()
+	}
+	if n, exact := o.Find("color"); !exact || n.Value != "blue" {
+		t.Logf("color should be taken from defaults")
+		t.Fail()
+	}
+	if n, exact := o.Find("log/level/x"); exact || n.Key() != "log/level" {
+		t.Logf("log/level/x should find log/level")
+		t.Fail()
+	}
+	got := ""
+	o.Do(func(v interface{}) { got += v.(string) + " " })
+	if got != "blue info debug l " {
+		t.Logf("Do should visit blue info debug l, visited %s", got)
+		t.Fail()
+	}
+	if p := o.Prefix("log"); len(p) != 2 {
+		t.Logf("Prefix(log) should return 2 nodes, got %d", len(p))
+		t.Fail()
+	}
+}
@@ -0,0 +1,101 @@
+package radix
+
+import (
+	"sort"
+)
+
+// Perfect is a read-only index of the keys of a tree built on a minimal perfect hash function:
+// every key hashes to its own slot, so a lookup computes two hashes and compares one key. It is
+// built with hash and displace: keys are hashed into buckets and for each bucket a seed is
+// searched that sends its keys to free slots.
+type Perfect struct {
+	seeds  []uint32 // seed of each bucket
+	keys   []string
+	values []interface{}
+}
+
+// Perfect returns a Perfect index holding the keys of r and their values. Keys are relative to r.
+func (r *Radix) Perfect() *Perfect {
+	var keys []string
+	var values []interface{}
+	r.ordered("", func(key string, n *Radix) bool {
+		keys = append(keys, key)
+		values = append(values, n.Value)
+		return true
+	})
+	p := &Perfect{seeds: make([]uint32, len(keys)/2+1), keys: make([]string, len(keys)), values: make([]interface{}, len(keys))}
+	if len(keys) == 0 {
+		return p
+	}
+	buckets := make([][]int, len(p.seeds))
+	for i, k := range keys {
+		b := perfectHash(k, 0) % uint64(len(buckets))
+		buckets[b] = append(buckets[b], i)
+	}
+	order := make([]int, len(buckets))
+	for i := range order {
+		order[i] = i
+	}
+	// Place the largest buckets first, while there are many free slots.
+	sort.Slice(order, func(i, j int) bool { return len(buckets[order[i]]) > len(buckets[order[j]]) })
+
+	used := make([]bool, len(keys))
+	slots := make([]uint64, 0, 8)
+	for _, b := range order {
+		if len(buckets[b]) == 0 {
+			break
+		}
+	seed:
+		for seed := uint32(1); ; seed++ {
+			slots = slots[:0]
+			for _, i := range buckets[b] {
+				s := perfectHash(keys[i], seed) % uint64(len(keys))
+				if used[s] {
+					continue seed
+				}
+				for _, t := range slots {
+					if t == s {
+						continue seed
+					}
+				}
+				slots = append(slots, s)
+			}
+			p.seeds[b] = seed
+			for j, i := range buckets[b] {
+				used[slots[j]] = true
+				p.keys[slots[j]], p.values[slots[j]] = keys[i], values[i]
+			}
+			break
+		}
+	}
+	return p
+}
+
+// Len returns the number of keys in p.
+func (p *Perfect) Len() int { return len(p.keys) }
+
+// Find returns the value of key and true, or nil and false if p does not hold key.
+func (p *Perfect) Find(key string) (interface{}, bool) {
+	if len(p.keys) == 0 {
+		return nil, false
+	}
+	seed := p.seeds[perfectHash(key, 0)%uint64(len(p.seeds))]
+	s := perfectHash(key, seed) % uint64(len(p.keys))
+	if p.keys[s] != key {
+		return nil, false
+	}
+	return p.values[s], true
+}
+
+// perfectHash is FNV-1a, seeded and with a final mix so seeds give independent hashes.
+func perfectHash(key string, seed uint32) uint64 {
+	h := uint64(14695981039346656037) ^ uint64(seed)*0x9e3779b97f4a7c15
+	for i := 0; i < len(key); i++ {
+		h ^= uint64(key[i])
+		h *= 1099511628211
+	}
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	return h
+}
@@ -0,0 +1,32 @@
+package radix
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestPerfect(t *testing.T) {
+	r := New()
+	for i := 0; i < 10000; i++ {
+		r.Insert("key/"+strconv.Itoa(i), i)
+	}
+	p := r.Perfect()
+	if p.Len() != 10000 {
+		t.Fatalf("index should hold 10000 keys, holds %d", p.Len())
+	}
+	for i := 0; i < 10000; i++ {
+		if v, ok := p.Find("key/" + strconv.Itoa(i)); !ok || v != i {
+			t.Fatalf("key/%d should be found with value %d, found %v", i, i, v)
+		}
+	}
+	for _, k := range []string{"key/10000", "key/", "", "other"} {
+		if _, ok := p.Find(k); ok {
+			t.Logf("%q should not be found", k)
+			t.Fail()
+		}
+	}
+	if _, ok := New().Perfect().Find("a"); ok {
+		t.Logf("empty index should not find anything")
+		t.Fail()
+	}
+}
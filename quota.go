@@ -0,0 +1,91 @@
+package radix
+
+import (
+	"strconv"
+)
+
+// QuotaError is returned when an insert would exceed the quota of a prefix, see SetQuota.
+// It matches ErrLimit with errors.Is.
+type QuotaError struct {
+	Prefix string
+	Max    int
+}
+
+func (e *QuotaError) Error() string {
+	return "radix: quota of " + strconv.Itoa(e.Max) + " keys under " + strconv.Quote(e.Prefix) + " exceeded"
+}
+
+// Is returns true if target is ErrLimit.
+func (e *QuotaError) Is(target error) bool { return target == ErrLimit }
+
+type quota struct {
+	max   int
+	count int // keys under the prefix
+}
+
+// SetQuota limits the number of keys starting with prefix to max. Insert and TryInsert
+// refuse new keys over the quota, TryInsert returns a *QuotaError. Keys already present are
+// not removed when a quota is lowered. A max of 0 removes the quota. Quotas may be nested,
+// a key must fit in all quotas of its prefixes. r must be the root of the radix tree.
+func (r *Radix) SetQuota(prefix string, max int) {
+	t := r.settings()
+	if t.quotas == nil {
+		t.quotas = New()
+	}
+	if max == 0 {
+		if prefix == "" {
+			t.quotas.set(nil)
+		} else {
+			t.quotas.Remove(prefix)
+		}
+		return
+	}
+	q := &quota{max: max}
+	if n := r.under(prefix); n != nil {
+		q.count = n.Len()
+	}
+	if prefix == "" {
+		t.quotas.set(q)
+		return
+	}
+	t.quotas.Insert(prefix, q)
+}
+
+// quotasOf calls f for the quota of each prefix of key.
+func (t *tree) quotasOf(key string, f func(prefix string, q *quota)) {
+	if t.quotas == nil {
+		return
+	}
+	t.quotas.prefixesOf(key, func(prefix string, v interface{}) { f(prefix, v.(*quota)) })
+}
+
+// checkQuota returns an error if adding key exceeds a quota.
+func (t *tree) checkQuota(key string) error {
+	var err error
+	t.quotasOf(key, func(prefix string, q *quota) {
+		if err == nil && q.count+1 > q.max {
+			err = &QuotaError{Prefix: prefix, Max: q.max}
+		}
+	})
+	return err
+}
+
+// quota adds delta to the key counts of the quotas of key.
+func (t *tree) quota(key string, delta int) {
+	t.quotasOf(key, func(_ string, q *quota) { q.count += delta })
+}
+
+// requota recounts the keys of all quotas.
+func (r *Radix) requota() {
+	t := r.tree
+	if t == nil || t.quotas == nil {
+		return
+	}
+	t.quotas.walk("", func(prefix string, n *Radix) {
+		q := n.Value.(*quota)
+		q.count = 0
+		if u := r.under(prefix); u != nil {
+			q.count = u.Len()
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package radix
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQuota(t *testing.T) {
+	r := New()
+	r.Insert("tenant/a/0", 0)
+	r.SetQuota("tenant/a/", 2)
+	r.SetQuota("tenant/", 3)
+	if _, err := r.TryInsert("tenant/a/1", 1); err != nil {
+		t.Fatalf("tenant/a/1 fits in the quota: %s", err)
+	}
+	_, err := r.TryInsert("tenant/a/2", 2)
+	var qe *QuotaError
+	if !errors.As(err, &qe) || qe.Prefix != "tenant/a/" || !errors.Is(err, ErrLimit) {
+		t.Fatalf("tenant/a/2 should exceed the quota of tenant/a/, got %v", err)
+	}
+	if _, err := r.TryInsert("tenant/a/1", "overwrite"); err != nil {
+		t.Logf("overwriting a key should not count against the quota: %s", err)
+		t.Fail()
+	}
+	if _, err := r.TryInsert("tenant/b/0", 0); err != nil {
+		t.Fatalf("tenant/b/0 fits in the quota of tenant/: %s", err)
+	}
+	if _, err := r.TryInsert("tenant/b/1", 0); !errors.As(err, &qe) || qe.Prefix != "tenant/" {
+		t.Logf("tenant/b/1 should exceed the quota of tenant/, got %v", err)
+		t.Fail()
+	}
+	r.Remove("tenant/a/0")
+	if _, err := r.TryInsert("tenant/b/1", 0); err != nil {
+		t.Logf("after Remove tenant/b/1 should fit: %s", err)
+		t.Fail()
+	}
+	r.Namespace("tenant/b/").Clear()
+	r.SetQuota("tenant/", 0)
+	for _, k := range []string{"tenant/c/0", "tenant/c/1", "tenant/c/2"} {
+		if _, err := r.TryInsert(k, 0); err != nil {
+			t.Logf("without a quota on tenant/ %s should fit: %s", k, err)
+			t.Fail()
+		}
+	}
+}
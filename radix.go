@@ -7,8 +7,28 @@
 //
 // Also see http://en.wikipedia.org/wiki/Radix_tree for more information.
 //
+// The core of the package does not use fmt, reflect or encoding/json, so it can be
+// used with TinyGo. When built with the tinygo build tag (TinyGo sets it), the parts
+// that need those packages are left out: Format, MarshalNestedJSON, MatchRegexp, Checked,
+// Handler, FlateCompressor, EncryptWriter and DecryptReader. Compressed snapshots and deltas
+// cannot be written or read in such a build either.
+//
 package radix
 
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrExists is returned when a key is already present in the tree.
+var ErrExists = errors.New("radix: key exists")
+
+// SkipSubtree is used as a return value from the function passed to Walk to indicate that
+// the keys below the current key are to be skipped. It is not returned as an error by Walk.
+var SkipSubtree = errors.New("radix: skip this subtree")
+
 // longestCommonPrefix returns the longest prefiex key and bar have
 // in common.
 func longestCommonPrefix(key, bar string) (string, int) {
@@ -79,18 +99,92 @@ func rightMostChild(m map[byte]*Radix) (right byte) {
 
 // Radix represents a radix tree.
 type Radix struct {
-	// children maps the first letter of each child to the child.
+	// children maps the first letter of each child to the child. It is nil until the node
+	// gets its first child, see addChild; reading a nil map is fine.
 	children map[byte]*Radix
 	key      string
 	parent   *Radix // a pointer back to the parent
+	tree     *tree  // settings for the whole tree, only set on the root
+	ext      *extra // only set when one of the fields in it is used
+	size     int    // number of non-nil Values in this subtree, see Len
 
-	// The contents of the radix node.
+	// The contents of the radix node. It may be replaced by another non-nil value directly, but
+	// only Insert and Remove may change it from or to nil, as they keep the counts of the tree.
 	Value interface{}
 }
 
+// set sets the Value of n and updates the sizes of n and the nodes above it.
+func (n *Radix) set(value interface{}) {
+	switch {
+	case n.Value == nil && value != nil:
+		n.grow(1)
+	case n.Value != nil && value == nil:
+		n.grow(-1)
+	}
+	n.Value = value
+}
+
+// grow adds delta to the size of n and the nodes above it.
+func (n *Radix) grow(delta int) {
+	for ; n != nil; n = n.parent {
+		n.size += delta
+	}
+}
+
+// addChild adds child to the children of n, allocating the map of n on the first child.
+func (n *Radix) addChild(child *Radix) {
+	if n.children == nil {
+		n.children = make(map[byte]*Radix)
+	}
+	n.children[child.key[0]] = child
+}
+
+// resize sets the size of n from its Value and the sizes of its children and returns it.
+func (n *Radix) resize() int {
+	n.size = 0
+	if n.Value != nil {
+		n.size = 1
+	}
+	for _, child := range n.children {
+		n.size += child.size
+	}
+	return n.size
+}
+
+// extra holds the fields of a node that are only needed by some settings of the tree.
+type extra struct {
+	hits   uint64      // how often Find returned this node, see TrackAccess, updated atomically
+	rev    uint64      // revision of the last change to Value, see TrackModifications
+	maxRev uint64      // largest rev in this subtree
+	meta   interface{} // see SetMeta
+}
+
+// extra returns the extra fields of n, allocating them when needed.
+func (n *Radix) extra() *extra {
+	if n.ext == nil {
+		n.ext = new(extra)
+	}
+	return n.ext
+}
+
+// Meta returns the user data set on r with SetMeta, or nil.
+func (r *Radix) Meta() interface{} {
+	if r.ext == nil {
+		return nil
+	}
+	return r.ext.meta
+}
+
+// setMeta sets the Meta of n, without allocating the extra fields for a nil meta.
+func (n *Radix) setMeta(meta interface{}) {
+	if meta != nil || n.ext != nil {
+		n.extra().meta = meta
+	}
+}
+
 // New returns an initialized radix tree.
 func New() *Radix {
-	return &Radix{make(map[byte]*Radix), "", nil, nil}
+	return &Radix{}
 }
 
 func (r *Radix) String() string {
@@ -120,6 +214,121 @@ func (r *Radix) Key() (s string) {
 	return
 }
 
+// node returns the node stored under key, even when its Value is nil. If
+// there is no such node, nil is returned.
+func (r *Radix) node(key string) *Radix {
+	for key != "" {
+		child, ok := r.children[key[0]]
+		if !ok || len(key) < len(child.key) || key[:len(child.key)] != child.key {
+			return nil
+		}
+		key = key[len(child.key):]
+		r = child
+	}
+	return r
+}
+
+// under returns the highest node in r whose key starts with prefix, all keys
+// starting with prefix are stored in its subtree. If there is no such node,
+// nil is returned.
+func (r *Radix) under(prefix string) *Radix {
+	for prefix != "" {
+		child, ok := r.children[prefix[0]]
+		if !ok {
+			return nil
+		}
+		_, i := longestCommonPrefix(prefix, child.key)
+		if i == len(prefix) {
+			return child
+		}
+		if i < len(child.key) {
+			return nil
+		}
+		prefix = prefix[i:]
+		r = child
+	}
+	return r
+}
+
+// cut removes the subtree n from the tree it is part of. Nodes above n that are left
+// without Value, Meta and children are removed as well, cut returns how many. If n is
+// the root, all its children and its Value are removed.
+func (n *Radix) cut() int {
+	if n.parent == nil {
+		n.children = nil
+		n.Value = nil
+		n.size = 0
+		return 0
+	}
+	p := n.parent
+	p.grow(-n.size)
+	delete(p.children, n.key[0])
+	above := 0
+	for p.parent != nil && p.Value == nil && p.Meta() == nil && len(p.children) == 0 {
+		up := p.parent
+		delete(up.children, p.key[0])
+		p.parent = nil
+		p = up
+		above++
+	}
+	return above
+}
+
+// hasValue returns true if r or any of its descendants has a non-nil Value.
+func (r *Radix) hasValue() bool {
+	if r.Value != nil {
+		return true
+	}
+	for _, child := range r.children {
+		if child.hasValue() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPrefix returns true if any key with a non-nil Value in r starts with prefix.
+func (r *Radix) HasPrefix(prefix string) bool {
+	n := r.under(prefix)
+	return n != nil && n.hasValue()
+}
+
+// SetMeta sets the Meta of the node stored under key. Meta holds user data that is not part
+// of the stored value, for instance to tag a subtree. Unlike Value it may be set on internal
+// nodes. If there is no node under key one is created, with a nil Value. The node is returned,
+// or nil if it can not be created within the limits of the tree, see TryInsert.
+func (r *Radix) SetMeta(key string, meta interface{}) *Radix {
+	n := r.node(key)
+	if n == nil {
+		var err error
+		if n, err = r.TryInsert(key, nil); err != nil {
+			return nil
+		}
+	}
+	n.setMeta(meta)
+	return n
+}
+
+// FindMeta returns the Meta of the deepest node on the path to key that
+// has a non-nil Meta, i.e. the tag of the smallest tagged subtree key lives in.
+// If there is no such node, ok is false.
+func (r *Radix) FindMeta(key string) (meta interface{}, ok bool) {
+	for {
+		if m := r.Meta(); m != nil {
+			meta, ok = m, true
+		}
+		if key == "" {
+			return
+		}
+		child, found := r.children[key[0]]
+		if !found || len(key) < len(child.key) || key[:len(child.key)] != child.key {
+			return
+		}
+		key = key[len(child.key):]
+		r = child
+	}
+}
+
 // Up returns the first node above r which has a non-nil Value.
 // It terminates at the root and returns nil if that happens.
 func (r *Radix) Up() *Radix {
@@ -133,29 +342,39 @@ func (r *Radix) Up() *Radix {
 }
 
 // Insert inserts the value into the tree with the specified key. It returns the radix node
-// it just inserted, r must the root of the radix tree.
+// it just inserted, r must the root of the radix tree. If the tree has limits (see NewWithLimits)
+// and inserting key would exceed them, nothing is inserted and nil is returned, use TryInsert to
+// get the reason.
 func (r *Radix) Insert(key string, value interface{}) *Radix {
+	n, _ := r.TryInsert(key, value)
+	return n
+}
+
+func (r *Radix) insert(key string, value interface{}, t *tree) *Radix {
 	// look up the child starting with the same letter as key
 	// if there is no child with the same starting letter, insert a new one
 	child, ok := r.children[key[0]]
 	if !ok {
-		r.children[key[0]] = &Radix{make(map[byte]*Radix), key, r, value}
-		return r.children[key[0]]
+		child = t.newNode(key, r)
+		r.addChild(child)
+		child.set(value)
+		return child
 	}
 
 	if key == child.key {
-		child.Value = value
+		child.set(value)
 		return child
 	}
 
 	commonPrefix, prefixEnd := longestCommonPrefix(key, child.key)
 
 	if commonPrefix == child.key {
-		return child.Insert(key[prefixEnd:], value)
+		return child.insert(key[prefixEnd:], value, t)
 	}
 
 	// create new child node to replace current child
-	newChild := &Radix{make(map[byte]*Radix), commonPrefix, r, nil}
+	newChild := t.newNode(commonPrefix, r)
+	newChild.size = child.size
 
 	// replace child of current node with new child: map first letter of common prefix to new child
 	r.children[commonPrefix[0]] = newChild
@@ -164,18 +383,57 @@ func (r *Radix) Insert(key string, value interface{}) *Radix {
 	child.key = child.key[prefixEnd:]
 
 	// map old child's new first letter to old child as a child of the new child
-	newChild.children[child.key[0]] = child
+	newChild.addChild(child)
 	child.parent = newChild
 
 	// if there are key left of key, insert them into our new child
 	if key != newChild.key {
-		newChild.Insert(key[prefixEnd:], value)
-	} else {
-		newChild.Value = value
+		return newChild.insert(key[prefixEnd:], value, t)
 	}
+	newChild.set(value)
 	return newChild
 }
 
+// prefixesOf calls f for each key in r that is a prefix of key and its value, shortest first.
+func (r *Radix) prefixesOf(key string, f func(prefix string, value interface{})) {
+	n, prefix := r, ""
+	for {
+		if n.Value != nil {
+			f(prefix, n.Value)
+		}
+		rest := key[len(prefix):]
+		if rest == "" {
+			return
+		}
+		child, ok := n.children[rest[0]]
+		if !ok || !strings.HasPrefix(rest, child.key) {
+			return
+		}
+		n, prefix = child, prefix+child.key
+	}
+}
+
+// Intern returns the canonical copy of key stored in r. If key is not present,
+// a copy of it is inserted and returned. Intern stores the canonical string as the
+// Value of the node, so a tree used for interning should not hold other values. If key
+// can not be inserted, because it is empty or the limits or quotas of the tree would be
+// exceeded, the error is returned together with key itself, which is then not canonical.
+func (r *Radix) Intern(key string) (string, error) {
+	if n, exact := r.find(key); exact {
+		if s, ok := n.Uncompressed().(string); ok {
+			return s, nil
+		}
+	}
+	if key == "" {
+		return key, ErrEmptyKey
+	}
+	s := strings.Clone(key)
+	if _, err := r.TryInsert(s, s); err != nil {
+		return key, err
+	}
+	return s, nil
+}
+
 // Find returns the node associated with key,
 // r must be the root of the Radix tree, although this is not enforced. If the node is located
 // it is returned and exact is set to true. If the node found has a nil Value, Find will go
@@ -183,7 +441,19 @@ func (r *Radix) Insert(key string, value interface{}) *Radix {
 // Also if the node is not found, the immediate predecessor
 // is returned and exact is set to false. If this node also has a nil Value the same thing
 // happens: the tree is search upwards, until the first non-nil Value node is found. 
+// If accesses are tracked, the access counter of the returned node is incremented, see TrackAccess.
 func (r *Radix) Find(key string) (node *Radix, exact bool) {
+	if t := r.tree; t != nil && t.mounts != nil {
+		if m, rest := t.mounted(key); m != nil {
+			return m.find(rest)
+		}
+	}
+	node, exact = r.find(key)
+	r.tree.hit(node)
+	return
+}
+
+func (r *Radix) find(key string) (node *Radix, exact bool) {
 	if key == "" {
 		return nil, false
 	}
@@ -232,7 +502,120 @@ func (r *Radix) Find(key string) (node *Radix, exact bool) {
 	}
 
 	// find the key left of key in child
-	return child.Find(key[prefixEnd:])
+	return child.find(key[prefixEnd:])
+}
+
+// FindAbs works just like Find, but key is the full key from the root of the tree, regardless
+// of which node of the tree r is.
+func (r *Radix) FindAbs(key string) (node *Radix, exact bool) {
+	return r.root().Find(key)
+}
+
+// root returns the root of the tree r is part of.
+func (r *Radix) root() *Radix {
+	for r.parent != nil {
+		r = r.parent
+	}
+	return r
+}
+
+// Prefix returns the nodes with a non-nil Value whose keys, relative to r, start with prefix.
+// The nodes are returned in lexical order.
+func (r *Radix) Prefix(prefix string) []*Radix {
+	var nodes []*Radix
+	n := r.under(prefix)
+	if n == nil {
+		return nodes
+	}
+	n.ordered("", func(_ string, n *Radix) bool {
+		nodes = append(nodes, n)
+		return true
+	})
+	return nodes
+}
+
+// PrefixAny returns the nodes with a non-nil Value whose keys, relative to r, start with any of
+// prefixes, in lexical order. Each node is returned once, also when prefixes overlap. The
+// prefixes are looked up in lexical order, so prefixes sharing a prefix share their descent.
+func (r *Radix) PrefixAny(prefixes []string) []*Radix {
+	sorted := append([]string(nil), prefixes...)
+	sort.Strings(sorted)
+	var nodes []*Radix
+	d := &descent{root: r, p: r}
+	covered := ""
+	for i, prefix := range sorted {
+		if i > 0 && strings.HasPrefix(prefix, covered) {
+			continue
+		}
+		covered = prefix
+		if n := d.under(prefix); n != nil {
+			n.ordered("", func(_ string, n *Radix) bool {
+				nodes = append(nodes, n)
+				return true
+			})
+		}
+	}
+	return nodes
+}
+
+// SetUnder replaces the value of each key starting with prefix by the result of fn, called with
+// the key and its current value in lexical order. If fn returns nil the value is left as is.
+// The keys are updated in a single walk, each update counts as an Insert for revisions,
+// history and modification tracking. SetUnder returns the number of updated keys. fn must not
+// modify the tree. r must be the root of the radix tree.
+func (r *Radix) SetUnder(prefix string, fn func(key string, old interface{}) interface{}) int {
+	n := r.under(prefix)
+	if n == nil {
+		return 0
+	}
+	updated := 0
+	n.ordered(n.Key(), func(key string, n *Radix) bool {
+		old := n.Value
+		v := fn(key, n.Uncompressed())
+		if v == nil {
+			return true
+		}
+		updated++
+		t := r.tree
+		if t == nil {
+			n.Value = v
+			return true
+		}
+		v = t.compress(v)
+		n.Value = v
+		t.rev++
+		t.modified(n)
+		t.account(old, v)
+		t.record(key, old, v)
+		return true
+	})
+	return updated
+}
+
+// MinPrefix returns the node with the smallest key, relative to r, that starts with prefix.
+// If there is no such node, nil is returned.
+func (r *Radix) MinPrefix(prefix string) *Radix {
+	n := r.under(prefix)
+	if n == nil {
+		return nil
+	}
+	return first(n)
+}
+
+// MaxPrefix returns the node with the largest key, relative to r, that starts with prefix.
+// If there is no such node, nil is returned.
+func (r *Radix) MaxPrefix(prefix string) *Radix {
+	n := r.under(prefix)
+	if n == nil {
+		return nil
+	}
+	return last(n)
+}
+
+// PrefixAbs works just like Prefix, but prefix is a full key from the root of the tree,
+// regardless of which node of the tree r is.
+func (r *Radix) PrefixAbs(prefix string) []*Radix {
+	return r.root().Prefix(prefix)
 }
 
 // FindFunc works just like Find, but each non-nil Value of each node traversed during
@@ -243,7 +626,7 @@ func (r *Radix) FindFunc(key string, f func(interface{}) bool) (node *Radix, exa
 	if key == "" {
 		return nil, false, false
 	}
-	if r.Value != nil && f(r.Value) {
+	if r.Value != nil && f(r.Uncompressed()) {
 		return r, false, true
 	}
 
@@ -402,26 +785,373 @@ func (r *Radix) prev() *Radix {
 // Remove removes any value set to key. It returns the removed node or nil if the
 // node cannot be found.
 func (r *Radix) Remove(key string) *Radix {
-	child, ok := r.children[key[0]]
-	if !ok {
+	t := r.tree
+	if t == nil || t.bare {
+		return r.remove(key)
+	}
+	n := r.node(key)
+	if n == nil {
 		return nil
 	}
+	t.epoch++
+	old := n.Value
+	gone := n.removed()
+	x := r.remove(key)
+	if old != nil {
+		t.removedKey(key, old)
+	}
+	t.nodes -= gone
+	return x
+}
 
-	// if the correct end node is found...
-	if key == child.key {
+// removedKey updates the bookkeeping of t after the value old of key has been removed.
+func (t *tree) removedKey(key string, old interface{}) {
+	t.keys--
+	t.rev++
+	t.forget(key, old)
+}
+
+// forget calls the hooks of t for the removal of key, whose value was old. It leaves the counts
+// and the revision alone, so callers removing many keys can update those once.
+func (t *tree) forget(key string, old interface{}) {
+	t.bury(key)
+	t.dropped(key)
+	t.quota(key, -1)
+	t.account(old, nil)
+	t.record(key, old, nil)
+}
+
+// merge merges n with its only child: the child's key is appended to the key of n and
+// its Value, Meta and children are moved to n.
+func (n *Radix) merge() {
+	for _, subchild := range n.children {
+		// essentially moves the subchild up one level to replace n, while keeping the key of n
+		n.key = n.key + subchild.key
+		n.Value = subchild.Value
+		n.size = subchild.size
+		n.ext = subchild.ext
+		n.children = subchild.children
+		for _, grandchild := range n.children {
+			grandchild.parent = n
+		}
+	}
+}
+
+// DeleteRange removes the keys, relative to r, in the range [start, end) and returns how many
+// were removed. Subtrees whose keys all fall in the range are cut off as a whole, without descending
+// into them key by key; the counts of the tree are fixed once, at the end. r must be the root of
+// the radix tree.
+func (r *Radix) DeleteRange(start, end string) int {
+	if start >= end {
+		return 0
+	}
+	t := r.tree
+	removed := 0
+	gone := func(key string, old interface{}) {
+		if t != nil {
+			if removed == 0 {
+				t.rev++
+			}
+			t.forget(key, old)
+		}
+		removed++
+	}
+	nodes := r.deleteRange("", start, end, gone)
+	if t != nil && removed > 0 {
+		t.epoch++
+		t.keys -= removed
+		t.nodes -= nodes
+	}
+	return removed
+}
+
+// deleteRange removes the keys in [start, end) from the subtree r, whose key is key, and returns
+// how many nodes were removed. gone is called with the key and old value of each key removed.
+func (r *Radix) deleteRange(key, start, end string, gone func(string, interface{})) int {
+	nodes := 0
+	if r.Value != nil && key >= start && key < end && r.parent != nil {
+		gone(key, r.Value)
+		r.Value = nil
+	}
+	for k, child := range r.children {
+		ck := key + child.key
+		switch {
+		case ck >= end, ck < start && !strings.HasPrefix(start, ck):
+			// no key in the subtree is in the range
+			continue
+		case ck >= start && !strings.HasPrefix(end, ck):
+			// all keys in the subtree are in the range
+			delete(r.children, k)
+			nodes += child.detached(ck, gone)
+			continue
+		}
+		nodes += child.deleteRange(ck, start, end, gone)
+		if child.Value != nil || child.Meta() != nil {
+			continue
+		}
 		switch len(child.children) {
 		case 0:
-			delete(r.children, key[0])
+			delete(r.children, k)
+			nodes++
 		case 1:
-			for _, subchild := range child.children {
-				// essentially moves the subchild up one level to replace the child we want to delete, while keeping the key of child
-				child.key = child.key + subchild.key
-				child.Value = subchild.Value
-				child.children = subchild.children
-				child.parent = r
-			}
+			child.merge()
+			nodes++
+		}
+	}
+	r.resize()
+	return nodes
+}
+
+// detached calls gone for each key in the subtree r, whose key is key, after r has been cut
+// from its tree, and returns the number of nodes in r.
+func (r *Radix) detached(key string, gone func(string, interface{})) int {
+	nodes := 1
+	if r.Value != nil {
+		gone(key, r.Value)
+	}
+	for _, child := range r.children {
+		nodes += child.detached(key+child.key, gone)
+	}
+	return nodes
+}
+
+// CountRange returns the number of keys, relative to r, in the range [start, end). Subtrees inside
+// the range are counted with Len, so only the nodes on the paths to start and end are visited.
+func (r *Radix) CountRange(start, end string) int {
+	if start >= end {
+		return 0
+	}
+	return r.countRange("", start, end)
+}
+
+func (r *Radix) countRange(key, start, end string) int {
+	count := 0
+	if r.Value != nil && key >= start && key < end {
+		count++
+	}
+	for _, child := range r.children {
+		ck := key + child.key
+		switch {
+		case ck >= end, ck < start && !strings.HasPrefix(start, ck):
+		case ck >= start && !strings.HasPrefix(end, ck):
+			count += child.Len()
+		default:
+			count += child.countRange(ck, start, end)
+		}
+	}
+	return count
+}
+
+// RemoveMany removes the keys in keys and returns how many of them were present. The keys are
+// removed in lexical order, so keys sharing a prefix share their descent, and the nodes left
+// without a value are merged or removed once, when the descent leaves them. Keys that are already
+// sorted are not copied. r must be the root of the radix tree.
+func (r *Radix) RemoveMany(keys []string) int {
+	if !sort.StringsAreSorted(keys) {
+		keys = append([]string(nil), keys...)
+		sort.Strings(keys)
+	}
+	t := r.tree
+	if t != nil {
+		t.epoch++
+	}
+	removed := 0
+	d := &descent{root: r, p: r, leave: func(n *Radix) {
+		n.resize()
+		if n.Value != nil || n.Meta() != nil {
+			return
+		}
+		switch len(n.children) {
+		case 0:
+			delete(n.parent.children, n.key[0])
+		case 1:
+			n.merge()
 		default:
-			child.Value = nil
+			return
+		}
+		if t != nil {
+			t.nodes--
+		}
+	}}
+	for _, key := range keys {
+		n := d.node(key)
+		if n == nil || n == r || n.Value == nil {
+			continue
+		}
+		old := n.Value
+		n.Value = nil
+		removed++
+		if t != nil {
+			t.removedKey(key, old)
+		}
+	}
+	d.up("")
+	r.resize()
+	return removed
+}
+
+// CompareAndDelete removes key if its value is equal to expected and returns true if it did.
+// The values are compared with ==, which panics when they are not comparable, use
+// CompareAndDeleteFunc for those. r must be the root of the radix tree.
+func (r *Radix) CompareAndDelete(key string, expected interface{}) bool {
+	return r.CompareAndDeleteFunc(key, expected, func(a, b interface{}) bool { return a == b })
+}
+
+// CompareAndDeleteFunc works like CompareAndDelete, but the values are compared with eq.
+func (r *Radix) CompareAndDeleteFunc(key string, expected interface{}, eq func(a, b interface{}) bool) bool {
+	n, exact := r.find(key)
+	if !exact || !eq(n.Uncompressed(), expected) {
+		return false
+	}
+	r.Remove(key)
+	return true
+}
+
+// descent looks up keys given in lexical order. Each search starts from the deepest node found
+// for the previous key whose key is a prefix of the current one, so shared prefixes are only
+// descended once. The tree must not change during the descent, except for the nodes passed to
+// leave: it is called for each node the descent goes up from, which is not visited again.
+type descent struct {
+	root  *Radix
+	p     *Radix
+	pkey  string // key of p, relative to root
+	leave func(n *Radix)
+}
+
+// up goes up from p until pkey is a prefix of key.
+func (d *descent) up(key string) {
+	for d.p != d.root && (len(d.pkey) > len(key) || key[:len(d.pkey)] != d.pkey) {
+		n := d.p
+		d.pkey = d.pkey[:len(d.pkey)-len(n.key)]
+		d.p = n.parent
+		if d.leave != nil {
+			d.leave(n)
+		}
+	}
+}
+
+// node works like root.node(key).
+func (d *descent) node(key string) *Radix {
+	d.up(key)
+	n := d.p.node(key[len(d.pkey):])
+	if n != nil {
+		d.p, d.pkey = n, key
+	}
+	return n
+}
+
+// under works like root.under(prefix).
+func (d *descent) under(prefix string) *Radix {
+	d.up(prefix)
+	n, key := d.p, d.pkey
+	for rest := prefix[len(key):]; rest != ""; {
+		child, ok := n.children[rest[0]]
+		if !ok {
+			return nil
+		}
+		_, i := longestCommonPrefix(rest, child.key)
+		if i < len(rest) && i < len(child.key) {
+			return nil
+		}
+		n, key = child, key+child.key
+		rest = rest[i:]
+	}
+	d.p, d.pkey = n, key
+	return n
+}
+
+// Result is the result of looking up a key with MultiGet.
+type Result struct {
+	Key   string
+	Node  *Radix // the node stored under Key, or nil
+	Found bool   // true if the node exists and has a non-nil Value
+}
+
+// MultiGet looks up all keys in one call and returns the results in the same order as keys.
+// The keys are looked up in lexical order, so keys sharing a prefix share their descent. Unlike
+// Find, only exact matches are returned. r must be the root of the radix tree.
+func (r *Radix) MultiGet(keys []string) []Result {
+	results := make([]Result, len(keys))
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return keys[order[i]] < keys[order[j]] })
+	d := &descent{root: r, p: r}
+	for _, i := range order {
+		results[i].Key = keys[i]
+		if n := d.node(keys[i]); n != nil && n != r && n.Value != nil {
+			results[i].Node, results[i].Found = n, true
+		}
+	}
+	return results
+}
+
+// removed returns the number of nodes remove deletes from the tree when it removes n.
+func (n *Radix) removed() int {
+	if n.Meta() != nil {
+		return 0
+	}
+	switch len(n.children) {
+	case 0:
+		gone := 1
+		for p := n.parent; p.parent != nil && p.Value == nil && p.Meta() == nil && len(p.children) == 1; p = p.parent {
+			gone++
+		}
+		return gone
+	case 1:
+		return 1
+	}
+	return 0
+}
+
+// PopMin removes the smallest key from r and returns it together with its value. The value of
+// the root is the empty key, the smallest of all. If r is empty, ok is false. r must be the root
+// of the radix tree.
+func (r *Radix) PopMin() (key string, value interface{}, ok bool) {
+	return r.pop(first(r))
+}
+
+// PopMax removes the largest key from r and returns it together with its value.
+// If r is empty, ok is false. r must be the root of the radix tree.
+func (r *Radix) PopMax() (key string, value interface{}, ok bool) {
+	return r.pop(last(r))
+}
+
+func (r *Radix) pop(n *Radix) (key string, value interface{}, ok bool) {
+	if n == nil || n.Value == nil {
+		return "", nil, false
+	}
+	value = n.Uncompressed()
+	if n == r {
+		// The empty key, which Remove does not accept.
+		old := r.Value
+		r.set(nil)
+		if t := r.tree; t != nil {
+			t.removedKey("", old)
+		}
+		return "", value, true
+	}
+	key = n.Key()
+	r.Remove(key)
+	return key, value, true
+}
+
+func (r *Radix) remove(key string) *Radix {
+	child, ok := r.children[key[0]]
+	if !ok {
+		return nil
+	}
+
+	// if the correct end node is found...
+	if key == child.key {
+		if len(child.children) == 0 && child.Meta() == nil {
+			child.cut() // the Value is kept, the caller returns the node
+			return child
+		}
+		child.set(nil)
+		if len(child.children) == 1 && child.Meta() == nil {
+			child.merge()
 		}
 		return child
 	}
@@ -430,7 +1160,7 @@ func (r *Radix) Remove(key string) *Radix {
 	if child.key != commonPrefix {
 		return nil
 	}
-	return child.Remove(key[prefixEnd:])
+	return child.remove(key[prefixEnd:])
 }
 
 // Do traverses the tree r in an unordered fashion and calls function f on each (non-nil) node,
@@ -440,13 +1170,91 @@ func (r *Radix) Do(f func(interface{})) {
 		return
 	}
 	if r.Value != nil {
-		f(r.Value)
+		f(r.Uncompressed())
 	}
 	for _, child := range r.children {
 		child.Do(f)
 	}
 }
 
+// Walk calls f for each key in r and its value, in lexical order. Like all traversals in this
+// package, Walk only visits nodes that hold a Value: the internal nodes created when keys are
+// split are skipped. If f returns SkipSubtree, the keys that have the current key as a prefix
+// are skipped and the walk continues after them. If f returns another error the walk stops and
+// that error is returned. f must not modify the tree, see SafeDo. Keys are relative to r.
+func (r *Radix) Walk(f func(key string, value interface{}) error) error {
+	return r.walkFunc("", f)
+}
+
+func (r *Radix) walkFunc(key string, f func(key string, value interface{}) error) error {
+	if r.Value != nil {
+		switch err := f(key, r.Uncompressed()); err {
+		case nil:
+		case SkipSubtree:
+			return nil
+		default:
+			return err
+		}
+	}
+	for _, k := range sortedChildren(r.children) {
+		child := r.children[k]
+		if err := child.walkFunc(key+child.key, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SafeDo calls f for each key with a non-nil Value in r and its value, in lexical order. Unlike
+// Do, f may modify the tree, for instance Remove the current or any other key. Keys are collected
+// before f is first called: keys inserted during the traversal are not visited and keys removed
+// before they are reached are skipped. Keys are relative to r.
+func (r *Radix) SafeDo(f func(key string, value interface{})) {
+	var keys []string
+	r.ordered("", func(key string, _ *Radix) bool {
+		keys = append(keys, key)
+		return true
+	})
+	for _, key := range keys {
+		if key == "" {
+			if r.Value != nil {
+				f(key, r.Uncompressed())
+			}
+			continue
+		}
+		if n, exact := r.find(key); exact {
+			f(key, n.Uncompressed())
+		}
+	}
+}
+
+// walk calls f for each node with a non-nil Value in the subtree r, in an
+// unordered fashion. The key given to f is the key of the node relative to r, prefixed
+// with key.
+func (r *Radix) walk(key string, f func(key string, n *Radix)) {
+	if r.Value != nil {
+		f(key, r)
+	}
+	for _, child := range r.children {
+		child.walk(key+child.key, f)
+	}
+}
+
+// ordered works like walk, but visits the nodes in lexical order. If f returns false
+// the walk stops and ordered returns false.
+func (r *Radix) ordered(key string, f func(key string, n *Radix) bool) bool {
+	if r.Value != nil && !f(key, r) {
+		return false
+	}
+	for _, k := range sortedChildren(r.children) {
+		child := r.children[k]
+		if !child.ordered(key+child.key, f) {
+			return false
+		}
+	}
+	return true
+}
+
 // NextDo traverses the tree r in Next-order and calls function f on each node,
 // f's parameter is be r.Value, f will never be called with a nil value.
 func (r *Radix) NextDo(f func(interface{})) {
@@ -459,13 +1267,13 @@ func (r *Radix) NextDo(f func(interface{})) {
 	// r.Value still may be nil, because there is no guarantee the 
 	// node after the root's node has a value.
 	if r.Value != nil {
-		f(r.Value)
+		f(r.Uncompressed())
 	}
 	k := r.Key()	// This will always be something meaningful.
 	r = r.Next()
 	for r.Key() != k {
 		if r.Value != nil {
-			f(r.Value)
+			f(r.Uncompressed())
 		}
 		r = r.Next()
 	}
@@ -481,28 +1289,164 @@ func (r *Radix) PrevDo(f func(interface{})) {
 		r = r.Next()
 	}
 	if r.Value != nil {
-		f(r.Value)
+		f(r.Uncompressed())
 	}
 	k := r.Key()	// Will be meaningful.
 	r = r.Prev()
 	for r.Key() != k {
 		if r.Value != nil {
-			f(r.Value)
+			f(r.Uncompressed())
 		}
 		r = r.Prev()
 	}
 }
 
-// Len computes the number of nodes in the radix tree r.
+// Len returns the number of keys in the radix tree r. The count is kept in each node by Insert
+// and Remove, so Len takes constant time.
 func (r *Radix) Len() int {
-	i := 0
-	if r != nil {
+	if r == nil {
+		return 0
+	}
+	return r.size
+}
+
+// TrackAccess makes Find count, for each node, how often it returned the node, see
+// AccessCount and TopAccessed. The counters are updated atomically, so a tree can still be
+// read from several goroutines at once. r must be the root of the radix tree.
+func (r *Radix) TrackAccess() {
+	t := r.settings()
+	t.access = true
+	r.recount() // allocates the counters
+}
+
+// hit counts an access to n, if accesses are tracked.
+func (t *tree) hit(n *Radix) {
+	if t != nil && t.access && n != nil && n.ext != nil {
+		atomic.AddUint64(&n.ext.hits, 1)
+	}
+}
+
+// hits returns the access counter of n.
+func (n *Radix) hits() uint64 {
+	if n.ext == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&n.ext.hits)
+}
+
+// AccessCount returns how many times Find has returned the node stored under key, see
+// TrackAccess. If key is not found 0 is returned. Calling AccessCount does not change the counter.
+func (r *Radix) AccessCount(key string) uint64 {
+	n, exact := r.find(key)
+	if !exact {
+		return 0
+	}
+	return n.hits()
+}
+
+// TopAccessed returns (at most) n nodes with the highest access counts,
+// highest first. Nodes that have never been returned by Find are not included.
+func (r *Radix) TopAccessed(n int) []*Radix {
+	var hot []*Radix
+	var collect func(*Radix)
+	collect = func(r *Radix) {
+		if r.Value != nil && r.hits() > 0 {
+			hot = append(hot, r)
+		}
+		for _, child := range r.children {
+			collect(child)
+		}
+	}
+	collect(r)
+	sort.Slice(hot, func(i, j int) bool {
+		if hi, hj := hot[i].hits(), hot[j].hits(); hi != hj {
+			return hi > hj
+		}
+		return hot[i].Key() < hot[j].Key()
+	})
+	if len(hot) > n {
+		hot = hot[:n]
+	}
+	return hot
+}
+
+// SplitRanges returns at most n-1 pivot keys that divide the keys in r into n
+// ranges holding roughly the same number of keys. The first range holds the keys
+// smaller than the first pivot, range i the keys k with pivot[i-1] <= k < pivot[i]
+// and the last range the keys larger than or equal to the last pivot. When r holds
+// fewer than n keys, fewer pivots are returned.
+func (r *Radix) SplitRanges(n int) []string {
+	total := r.Len()
+	pivots := []string{}
+	for i := 1; i < n; i++ {
+		rank := i * total / n
+		if rank == 0 {
+			continue
+		}
+		k := r.nth(rank).Key()
+		if len(pivots) > 0 && pivots[len(pivots)-1] == k {
+			continue
+		}
+		pivots = append(pivots, k)
+	}
+	return pivots
+}
+
+// nth returns the node with a non-nil Value at (zero based) position i in lexical
+// order. The sizes of the subtrees are used to skip over them.
+func (r *Radix) nth(i int) *Radix {
+descend:
+	for {
 		if r.Value != nil {
-			i++
+			if i == 0 {
+				return r
+			}
+			i--
+		}
+		for _, k := range sortedChildren(r.children) {
+			child := r.children[k]
+			l := child.Len()
+			if i < l {
+				r = child
+				continue descend
+			}
+			i -= l
+		}
+		return nil
+	}
+}
+
+// PrefixCount holds a prefix and the number of keys starting with it.
+type PrefixCount struct {
+	Prefix string
+	Count  int
+}
+
+// HeaviestPrefixes returns the (at most) k prefixes of length depth that have the
+// most keys starting with them, heaviest first. Keys shorter than depth are not counted.
+func (r *Radix) HeaviestPrefixes(depth, k int) []PrefixCount {
+	counts := []PrefixCount{}
+	var visit func(r *Radix, key string)
+	visit = func(r *Radix, key string) {
+		if len(key) >= depth {
+			if l := r.Len(); l > 0 {
+				counts = append(counts, PrefixCount{key[:depth], l})
+			}
+			return
 		}
 		for _, child := range r.children {
-			i += child.Len()
+			visit(child, key+child.key)
+		}
+	}
+	visit(r, "")
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
 		}
+		return counts[i].Prefix < counts[j].Prefix
+	})
+	if len(counts) > k {
+		counts = counts[:k]
 	}
-	return i
+	return counts
 }
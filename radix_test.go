@@ -1,8 +1,13 @@
 package radix
 
 import (
+	"errors"
 	"fmt"
+	"math/rand"
+	"sync"
 	"testing"
+	"time"
+	"unsafe"
 )
 
 func printit(r *Radix, level int) {
@@ -230,3 +235,845 @@ func BenchmarkFind(b *testing.B) {
 		_, _ = r.Find("tester")
 	}
 }
+
+func TestAccessCount(t *testing.T) {
+	r := radixtree()
+	r.Find("tester")
+	if c := r.AccessCount("tester"); c != 0 {
+		t.Logf("Find should not count accesses unless they are tracked, counted %d", c)
+		t.Fail()
+	}
+	r.TrackAccess()
+	for i := 0; i < 3; i++ {
+		r.Find("tester")
+	}
+	r.Find("team")
+	r.Find("testing") // finds test
+	if c := r.AccessCount("tester"); c != 3 {
+		t.Logf("AccessCount of tester should be 3, is %d", c)
+		t.Fail()
+	}
+	if c := r.AccessCount("test"); c != 1 {
+		t.Logf("AccessCount of test should be 1, is %d", c)
+		t.Fail()
+	}
+	top := r.TopAccessed(2)
+	if len(top) != 2 || top[0].Key() != "tester" || top[1].Key() != "team" {
+		t.Logf("TopAccessed should return tester and team")
+		t.Fail()
+	}
+}
+
+func TestAccessCountConcurrent(t *testing.T) {
+	r := radixtree()
+	r.TrackAccess()
+	r.Insert("toaster", "new") // nodes inserted after TrackAccess are counted as well
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				r.Find("toaster")
+			}
+		}()
+	}
+	wg.Wait()
+	if c := r.AccessCount("toaster"); c != 400 {
+		t.Logf("AccessCount of toaster should be 400, is %d", c)
+		t.Fail()
+	}
+}
+
+func TestMeta(t *testing.T) {
+	r := radixtree()
+	r.Insert("teams/a", "a")
+	r.Insert("teams/b", "b")
+	// "teams/" is not a node in the tree yet.
+	n := r.SetMeta("teams/", "owner: bob")
+	if n.Key() != "teams/" || n.Value != nil {
+		t.Logf("SetMeta should create an internal node for teams/")
+		t.Fail()
+	}
+	if m, ok := r.FindMeta("teams/b"); !ok || m != "owner: bob" {
+		t.Logf("teams/b should be tagged with owner: bob, is %v", m)
+		t.Fail()
+	}
+	if _, ok := r.FindMeta("team"); ok {
+		t.Logf("team should not be tagged")
+		t.Fail()
+	}
+	if x, exact := r.Find("teams/a"); !exact || x.Value != "a" {
+		t.Logf("teams/a should still be found")
+		t.Fail()
+	}
+	r.Remove("teams/a")
+	r.Remove("teams/b")
+	if m, ok := r.FindMeta("teams/"); !ok || m != "owner: bob" {
+		t.Logf("teams/ should keep its tag after removing its children")
+		t.Fail()
+	}
+}
+
+func TestSplitRanges(t *testing.T) {
+	r := New()
+	for i := 0; i < 100; i++ {
+		r.Insert(fmt.Sprintf("key%02d", i), i)
+	}
+	pivots := r.SplitRanges(4)
+	want := []string{"key25", "key50", "key75"}
+	if fmt.Sprint(pivots) != fmt.Sprint(want) {
+		t.Logf("SplitRanges(4) should be %v, is %v", want, pivots)
+		t.Fail()
+	}
+	if p := New().SplitRanges(4); len(p) != 0 {
+		t.Logf("SplitRanges on an empty tree should return no pivots, got %v", p)
+		t.Fail()
+	}
+}
+
+func TestHasPrefix(t *testing.T) {
+	r := radixtree()
+	for p, want := range map[string]bool{
+		"":        true,
+		"t":       true,
+		"tes":     true,
+		"tester":  true,
+		"testers": false,
+		"tea":     true,
+		"teb":     false,
+		"x":       false,
+	} {
+		if r.HasPrefix(p) != want {
+			t.Logf("HasPrefix(%s) should be %t", p, want)
+			t.Fail()
+		}
+	}
+	if New().HasPrefix("") {
+		t.Logf("empty tree should not have any prefix")
+		t.Fail()
+	}
+}
+
+func TestIntern(t *testing.T) {
+	r := New()
+	buf := []byte("tester")
+	a, _ := r.Intern(string(buf[:4]))
+	b, _ := r.Intern("test")
+	if a != "test" || b != "test" {
+		t.Logf("Intern should return test, got %s and %s", a, b)
+		t.Fail()
+	}
+	if unsafe.StringData(a) != unsafe.StringData(b) {
+		t.Logf("Intern should return the same copy of test")
+		t.Fail()
+	}
+	if r.Len() != 1 {
+		t.Logf("Intern should store test once, Len is %d", r.Len())
+		t.Fail()
+	}
+
+	r = NewWithLimits(Limits{MaxKeys: 1})
+	r.Intern("a")
+	if s, err := r.Intern("b"); !errors.Is(err, ErrLimit) || s != "b" {
+		t.Logf("Intern beyond MaxKeys should fail with ErrLimit, got %q %v", s, err)
+		t.Fail()
+	}
+	if _, err := r.Intern(""); !errors.Is(err, ErrEmptyKey) {
+		t.Logf("Intern of the empty key should fail with ErrEmptyKey, got %v", err)
+		t.Fail()
+	}
+}
+
+func TestHeaviestPrefixes(t *testing.T) {
+	r := New()
+	for _, k := range []string{"com.a", "com.b", "com.c", "org.a", "org.b", "net.a", "n"} {
+		r.Insert(k, k)
+	}
+	h := r.HeaviestPrefixes(3, 2)
+	want := []PrefixCount{{"com", 3}, {"org", 2}}
+	if fmt.Sprint(h) != fmt.Sprint(want) {
+		t.Logf("HeaviestPrefixes(3, 2) should be %v, is %v", want, h)
+		t.Fail()
+	}
+	if h := r.HeaviestPrefixes(0, 1); len(h) != 1 || h[0].Count != 7 {
+		t.Logf("HeaviestPrefixes(0, 1) should count all keys, is %v", h)
+		t.Fail()
+	}
+}
+
+func TestPopMinMax(t *testing.T) {
+	r := radixtree()
+	r.Insert("a", "a")
+	r.Insert("z", "z")
+	if k, v, ok := r.PopMin(); !ok || k != "a" || v != "a" {
+		t.Logf("PopMin should return a, got %s", k)
+		t.Fail()
+	}
+	if k, _, _ := r.PopMax(); k != "z" {
+		t.Logf("PopMax should return z, got %s", k)
+		t.Fail()
+	}
+	want := []string{"te", "team", "test", "tester"}
+	for _, w := range want {
+		if k, _, _ := r.PopMin(); k != w {
+			t.Logf("PopMin should return %s, got %s", w, k)
+			t.Fail()
+		}
+	}
+	if _, _, ok := r.PopMax(); ok {
+		t.Logf("PopMax on an empty tree should return false")
+		t.Fail()
+	}
+	if len(r.children) != 0 {
+		t.Logf("all nodes should be removed from the tree")
+		t.Fail()
+	}
+}
+
+func TestPopRoot(t *testing.T) {
+	for _, settings := range []bool{false, true} {
+		r := New()
+		if settings {
+			r.KeepHistory(10)
+		}
+		r.setRoot("root")
+		r.Insert("a", "a")
+		if k, v, ok := r.PopMin(); !ok || k != "" || v != "root" {
+			t.Logf("PopMin should return the empty key and root, got %q %v %t", k, v, ok)
+			t.Fail()
+		}
+		r.setRoot("root")
+		for _, want := range []string{"a", ""} {
+			if k, _, ok := r.PopMax(); !ok || k != want {
+				t.Logf("PopMax should return %q, got %q %t", want, k, ok)
+				t.Fail()
+			}
+		}
+		if _, _, ok := r.PopMax(); ok || r.Len() != 0 || r.Value != nil {
+			t.Logf("tree should be empty, holds %d keys", r.Len())
+			t.Fail()
+		}
+		checkCounts(t, r, "PopMax")
+		if settings {
+			if r.Undo(1); r.Value != "root" || r.Len() != 1 {
+				t.Logf("Undo should restore the value of the root, is %v", r.Value)
+				t.Fail()
+			}
+			checkCounts(t, r, "Undo")
+		}
+	}
+}
+
+func TestRemovePrune(t *testing.T) {
+	r := NewWithLimits(Limits{})
+	r.Insert("test", "a")
+	r.Insert("team", "b")
+	r.Remove("test")
+	r.Remove("team")
+	if len(r.children) != 0 || r.tree.nodes != 0 {
+		t.Logf("removing all keys should remove all nodes, %d nodes left", r.tree.nodes)
+		t.Fail()
+	}
+	r.Insert("nl.miek", "a")
+	r.Insert("nl.miek.a", "b")
+	r.Insert("nl.miek.a.b", "c")
+	r.Remove("nl.miek.a")
+	if n, _ := r.Find("nl.miek.a.b"); n.Up().Key() != "nl.miek" {
+		t.Logf("nodes moved up by Remove should point to their new parent")
+		t.Fail()
+	}
+	nodes := r.tree.nodes
+	r.recount()
+	if r.tree.nodes != nodes {
+		t.Logf("nodes should be counted correctly, %d != %d", nodes, r.tree.nodes)
+		t.Fail()
+	}
+}
+
+func TestFindPrefixAbs(t *testing.T) {
+	r := radixtree()
+	r.Insert("tea", "b")
+	inner, _ := r.Find("test")
+	if n, exact := inner.FindAbs("team"); !exact || n.Key() != "team" {
+		t.Logf("FindAbs(team) from test should find team")
+		t.Fail()
+	}
+	if n, exact := inner.Find("er"); !exact || n.Key() != "tester" {
+		t.Logf("Find(er) from test should find tester")
+		t.Fail()
+	}
+	keys := ""
+	for _, n := range inner.PrefixAbs("tea") {
+		keys += n.Key() + " "
+	}
+	if keys != "tea team " {
+		t.Logf("PrefixAbs(tea) should be tea and team, is %s", keys)
+		t.Fail()
+	}
+	if p := inner.Prefix("e"); len(p) != 1 || p[0].Key() != "tester" {
+		t.Logf("Prefix(e) from test should be tester")
+		t.Fail()
+	}
+	if p := r.Prefix("x"); len(p) != 0 {
+		t.Logf("Prefix(x) should be empty")
+		t.Fail()
+	}
+}
+
+func TestRemoveMany(t *testing.T) {
+	r := New()
+	for i := 0; i < 100; i++ {
+		r.Insert(fmt.Sprintf("session/%03d", i), i)
+	}
+	r.Insert("session", "root")
+	var keys []string
+	for i := 99; i >= 0; i -= 2 {
+		keys = append(keys, fmt.Sprintf("session/%03d", i))
+	}
+	keys = append(keys, "session/999", "session/0")
+	if n := r.RemoveMany(keys); n != 50 {
+		t.Logf("RemoveMany should remove 50 keys, removed %d", n)
+		t.Fail()
+	}
+	if r.Len() != 51 {
+		t.Logf("51 keys should be left, Len is %d", r.Len())
+		t.Fail()
+	}
+	for i := 0; i < 100; i++ {
+		_, exact := r.Find(fmt.Sprintf("session/%03d", i))
+		if exact != (i%2 == 0) {
+			t.Logf("session/%03d should be found: %t", i, i%2 == 0)
+			t.Fail()
+		}
+	}
+	keys = keys[:0]
+	for i := 0; i < 100; i += 2 {
+		keys = append(keys, fmt.Sprintf("session/%03d", i))
+	}
+	r.RemoveMany(keys)
+	n, _ := r.Find("session")
+	if r.Len() != 1 || len(n.children) != 0 {
+		t.Logf("only session should be left, without any children")
+		t.Fail()
+	}
+}
+
+func TestRemoveManyCount(t *testing.T) {
+	for _, settings := range []bool{false, true} {
+		r := New()
+		if settings {
+			r = NewWithSize(8)
+		}
+		r.Insert("ab", 1)
+		r.Insert("ac", 2)
+		if n := r.RemoveMany([]string{"a"}); n != 0 {
+			t.Logf("settings %t: a is not a key, RemoveMany removed %d", settings, n)
+			t.Fail()
+		}
+		r.Insert("a", 0)
+		if n := r.RemoveMany([]string{"a", "a"}); n != 1 {
+			t.Logf("settings %t: a is present once, RemoveMany removed %d", settings, n)
+			t.Fail()
+		}
+	}
+}
+
+// keysOf returns the keys in r with their values, in lexical order.
+func keysOf(r *Radix) []string {
+	var keys []string
+	r.Walk(func(key string, value interface{}) error {
+		keys = append(keys, fmt.Sprintf("%s=%v", key, value))
+		return nil
+	})
+	return keys
+}
+
+// checkCompact reports the nodes below the root of r that hold no value and have fewer than two children.
+func checkCompact(t *testing.T, r *Radix, what string) {
+	var visit func(key string, n *Radix)
+	visit = func(key string, n *Radix) {
+		if n != r && n.Value == nil && n.Meta() == nil && len(n.children) < 2 {
+			t.Logf("%s: node %q holds no value and has %d children", what, key, len(n.children))
+			t.Fail()
+		}
+		for _, child := range n.children {
+			visit(key+child.key, child)
+		}
+	}
+	visit("", r)
+}
+
+// checkCounts reports when the counts kept in the settings of r differ from recounting them.
+func checkCounts(t *testing.T, r *Radix, what string) {
+	tr := r.tree
+	if tr == nil {
+		return
+	}
+	keys, nodes := tr.keys, tr.nodes
+	r.recount()
+	if keys != tr.keys || nodes != tr.nodes {
+		t.Logf("%s: counted %d keys and %d nodes, there are %d and %d", what, keys, nodes, tr.keys, tr.nodes)
+		t.Fail()
+	}
+}
+
+func TestRemoveManyRandom(t *testing.T) {
+	for seed := int64(0); seed < 50; seed++ {
+		rnd := rand.New(rand.NewSource(seed))
+		var keys []string
+		for i := 0; i < 100; i++ {
+			b := make([]byte, 1+rnd.Intn(5))
+			for j := range b {
+				b[j] = "ab/"[rnd.Intn(3)]
+			}
+			keys = append(keys, string(b))
+		}
+		for _, settings := range []bool{false, true} {
+			many, loop := New(), New()
+			if settings {
+				many.KeepHistory(1000)
+				loop.KeepHistory(1000)
+			}
+			for _, k := range keys {
+				many.Insert(k, k)
+				loop.Insert(k, k)
+			}
+			gone := keys[:rnd.Intn(len(keys))]
+			for _, k := range gone {
+				loop.Remove(k)
+			}
+			many.RemoveMany(gone)
+			if a, b := fmt.Sprint(keysOf(many)), fmt.Sprint(keysOf(loop)); a != b {
+				t.Fatalf("seed %d: RemoveMany left\n%s\nRemove left\n%s", seed, a, b)
+			}
+			checkCompact(t, many, fmt.Sprintf("seed %d", seed))
+			checkCounts(t, many, fmt.Sprintf("seed %d", seed))
+		}
+	}
+}
+
+func BenchmarkRemoveMany(b *testing.B) {
+	keys := make([]string, 200000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("session/%04d/%03d", i/100, i%100)
+	}
+	shuffled := append([]string(nil), keys...)
+	rand.New(rand.NewSource(1)).Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	build := func(settings bool) *Radix {
+		r := New()
+		if settings {
+			r.KeepTombstones(0)
+		}
+		for _, k := range keys {
+			r.Insert(k, k)
+		}
+		return r
+	}
+	for _, settings := range []bool{false, true} {
+		for name, order := range map[string][]string{"sorted": keys, "shuffled": shuffled} {
+			b.Run(fmt.Sprintf("settings=%t/%s/RemoveMany", settings, name), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					r := build(settings)
+					b.StartTimer()
+					r.RemoveMany(order)
+				}
+			})
+			b.Run(fmt.Sprintf("settings=%t/%s/Remove", settings, name), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					r := build(settings)
+					b.StartTimer()
+					for _, k := range order {
+						r.Remove(k)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestMinMaxPrefix(t *testing.T) {
+	r := New()
+	for _, k := range []string{"log/2012-01-02", "log/2012-01-01", "log/2012-02-01", "logs", "tmp"} {
+		r.Insert(k, k)
+	}
+	if n := r.MinPrefix("log/"); n == nil || n.Key() != "log/2012-01-01" {
+		t.Logf("MinPrefix(log/) should be log/2012-01-01")
+		t.Fail()
+	}
+	if n := r.MaxPrefix("log/2012-01"); n == nil || n.Key() != "log/2012-01-02" {
+		t.Logf("MaxPrefix(log/2012-01) should be log/2012-01-02")
+		t.Fail()
+	}
+	if n := r.MaxPrefix("log"); n == nil || n.Key() != "logs" {
+		t.Logf("MaxPrefix(log) should be logs")
+		t.Fail()
+	}
+	if r.MinPrefix("var/") != nil {
+		t.Logf("MinPrefix(var/) should be nil")
+		t.Fail()
+	}
+}
+
+func TestSafeDo(t *testing.T) {
+	r := New()
+	for i := 0; i < 10; i++ {
+		r.Insert(fmt.Sprintf("lease/%d", i), i)
+	}
+	visited := 0
+	r.SafeDo(func(key string, value interface{}) {
+		visited++
+		if value.(int)%2 == 0 {
+			r.Remove(key)
+			r.Remove(fmt.Sprintf("lease/%d", value.(int)+1))
+			r.Insert("new/"+key, value)
+		}
+	})
+	if visited != 5 {
+		t.Logf("SafeDo should visit 5 keys, visited %d", visited)
+		t.Fail()
+	}
+	if r.Len() != 5 || r.HasPrefix("lease/") {
+		t.Logf("all leases should be expired and 5 new keys inserted, Len is %d", r.Len())
+		t.Fail()
+	}
+}
+
+func TestWalk(t *testing.T) {
+	r := New()
+	r.Insert("test", "a")
+	r.Insert("team", "b") // creates the internal node te
+	r.Insert("toast", "c")
+	keys := ""
+	err := r.Walk(func(key string, value interface{}) error {
+		if value == nil {
+			t.Logf("Walk should not visit nodes without a Value")
+			t.Fail()
+		}
+		keys += key + " "
+		return nil
+	})
+	if err != nil || keys != "team test toast " {
+		t.Logf("Walk should visit team test toast, visited %s", keys)
+		t.Fail()
+	}
+	stop := fmt.Errorf("stop")
+	n := 0
+	err = r.Walk(func(key string, value interface{}) error {
+		n++
+		return stop
+	})
+	if err != stop || n != 1 {
+		t.Logf("Walk should stop at the first error")
+		t.Fail()
+	}
+}
+
+func TestWalkSkipSubtree(t *testing.T) {
+	r := New()
+	for _, k := range []string{"a", "a/b", "a/b/c", "a/d", "ab", "b"} {
+		r.Insert(k, k)
+	}
+	keys := ""
+	err := r.Walk(func(key string, value interface{}) error {
+		keys += key + " "
+		if key == "a/b" {
+			return SkipSubtree
+		}
+		return nil
+	})
+	if err != nil || keys != "a a/b a/d ab b " {
+		t.Logf("Walk should skip a/b/c, visited %s, error %v", keys, err)
+		t.Fail()
+	}
+}
+
+func TestCompareAndDelete(t *testing.T) {
+	r := New()
+	r.Insert("lease", 1)
+	if r.CompareAndDelete("lease", 2) {
+		t.Logf("lease should not be deleted, its value is 1")
+		t.Fail()
+	}
+	if !r.CompareAndDelete("lease", 1) || r.Len() != 0 {
+		t.Logf("lease should be deleted")
+		t.Fail()
+	}
+	if r.CompareAndDelete("lease", 1) {
+		t.Logf("a missing key should not be deleted")
+		t.Fail()
+	}
+	r.Insert("slice", []int{1, 2})
+	eq := func(a, b interface{}) bool { return fmt.Sprint(a) == fmt.Sprint(b) }
+	if !r.CompareAndDeleteFunc("slice", []int{1, 2}, eq) {
+		t.Logf("slice should be deleted")
+		t.Fail()
+	}
+}
+
+func TestMultiGet(t *testing.T) {
+	r := radixtree()
+	r.Insert("toast", "b")
+	keys := []string{"toast", "tester", "te", "tes", "x", "team", "test"}
+	results := r.MultiGet(keys)
+	for i, res := range results {
+		want := i != 3 && i != 4
+		if res.Key != keys[i] || res.Found != want {
+			t.Logf("result %d should be %s (found %t), is %v", i, keys[i], want, res)
+			t.Fail()
+		}
+		if res.Found && res.Node.Key() != keys[i] {
+			t.Logf("result %d should hold the node of %s", i, keys[i])
+			t.Fail()
+		}
+	}
+}
+
+func TestPrefixAny(t *testing.T) {
+	r := New()
+	for _, k := range []string{"/a/1", "/a/2", "/ab", "/b/1", "/b/2/x", "/c"} {
+		r.Insert(k, k)
+	}
+	got := ""
+	for _, n := range r.PrefixAny([]string{"/b/", "/a/", "/a/1", "/b/2", "/d"}) {
+		got += n.Key() + " "
+	}
+	if got != "/a/1 /a/2 /b/1 /b/2/x " {
+		t.Logf("PrefixAny should return /a/1 /a/2 /b/1 /b/2/x, returned %s", got)
+		t.Fail()
+	}
+	if n := r.PrefixAny([]string{"/c", ""}); len(n) != r.Len() {
+		t.Logf("the empty prefix should return all %d keys, returned %d", r.Len(), len(n))
+		t.Fail()
+	}
+}
+
+func TestSetUnder(t *testing.T) {
+	r := New()
+	r.KeepHistory(10)
+	for _, k := range []string{"ns/a", "ns/b", "nsx", "other"} {
+		r.Insert(k, "old")
+	}
+	rev := r.Revision()
+	n := r.SetUnder("ns/", func(key string, old interface{}) interface{} {
+		if key == "ns/b" {
+			return nil
+		}
+		return key + ":new"
+	})
+	if n != 1 {
+		t.Logf("SetUnder should update 1 key, updated %d", n)
+		t.Fail()
+	}
+	if v, _ := r.Find("ns/a"); v.Value != "ns/a:new" {
+		t.Logf("ns/a should be updated, is %v", v.Value)
+		t.Fail()
+	}
+	for _, k := range []string{"ns/b", "nsx", "other"} {
+		if v, _ := r.Find(k); v.Value != "old" {
+			t.Logf("%s should not be updated", k)
+			t.Fail()
+		}
+	}
+	if r.Revision() != rev+1 {
+		t.Logf("SetUnder should count as one change, revision went from %d to %d", rev, r.Revision())
+		t.Fail()
+	}
+	r.Undo(1)
+	if v, _ := r.Find("ns/a"); v.Value != "old" {
+		t.Logf("Undo should restore ns/a, is %v", v.Value)
+		t.Fail()
+	}
+}
+
+func TestDeleteRange(t *testing.T) {
+	keys := []string{"2023-12-31", "2024-01-01/a", "2024-01-01/b", "2024-01-02", "2024-02-01", "2024-02-01/x", "2024-03-01"}
+	for _, settings := range []bool{false, true} {
+		r := New()
+		if settings {
+			r.KeepHistory(100)
+		}
+		for _, k := range keys {
+			r.Insert(k, k)
+		}
+		if n := r.DeleteRange("2024-01", "2024-02-01/"); n != 4 {
+			t.Logf("DeleteRange should remove 4 keys, removed %d", n)
+			t.Fail()
+		}
+		got := ""
+		r.Walk(func(key string, _ interface{}) error {
+			got += key + " "
+			return nil
+		})
+		if got != "2023-12-31 2024-02-01/x 2024-03-01 " {
+			t.Logf("tree should hold 2023-12-31 2024-02-01/x 2024-03-01, holds %s", got)
+			t.Fail()
+		}
+		var compact func(n *Radix) bool
+		compact = func(n *Radix) bool {
+			if n.parent != nil && n.Value == nil && len(n.children) < 2 {
+				return false
+			}
+			for _, child := range n.children {
+				if child.parent != n || !compact(child) {
+					return false
+				}
+			}
+			return true
+		}
+		if !compact(r) {
+			t.Logf("tree should not hold empty nodes with less than two children after DeleteRange")
+			t.Fail()
+		}
+		if r.Len() != 3 {
+			t.Logf("tree should hold 3 keys, holds %d", r.Len())
+			t.Fail()
+		}
+		checkCounts(t, r, "DeleteRange")
+		if settings {
+			if n := r.Undo(4); n != 4 || r.Len() != len(keys) {
+				t.Logf("Undo should restore the 4 keys removed, restored %d, tree holds %d keys", n, r.Len())
+				t.Fail()
+			}
+		}
+	}
+}
+
+func TestDeleteRangeRandom(t *testing.T) {
+	for seed := int64(0); seed < 50; seed++ {
+		rnd := rand.New(rand.NewSource(seed))
+		ranged, loop := New(), New()
+		ranged.KeepTombstones(time.Hour)
+		loop.KeepTombstones(time.Hour)
+		for i := 0; i < 100; i++ {
+			b := make([]byte, 1+rnd.Intn(5))
+			for j := range b {
+				b[j] = "ab/"[rnd.Intn(3)]
+			}
+			ranged.Insert(string(b), i)
+			loop.Insert(string(b), i)
+		}
+		start, end := "ab"[rnd.Intn(2):], "b/a"[rnd.Intn(3):]
+		var keys []string
+		c := loop.Cursor()
+		for ok := c.Seek(start); ok && c.Key() < end; ok = c.Next() {
+			keys = append(keys, c.Key())
+		}
+		for _, k := range keys {
+			loop.Remove(k)
+		}
+		if n := ranged.DeleteRange(start, end); n != len(keys) {
+			t.Logf("seed %d: DeleteRange(%q, %q) should remove %d keys, removed %d", seed, start, end, len(keys), n)
+			t.Fail()
+		}
+		if a, b := fmt.Sprint(keysOf(ranged)), fmt.Sprint(keysOf(loop)); a != b {
+			t.Fatalf("seed %d: DeleteRange left\n%s\nRemove left\n%s", seed, a, b)
+		}
+		if n := len(ranged.Tombstones(0)); n != len(keys) {
+			t.Logf("seed %d: DeleteRange should leave %d tombstones, left %d", seed, len(keys), n)
+			t.Fail()
+		}
+		checkCompact(t, ranged, fmt.Sprintf("seed %d", seed))
+		checkCounts(t, ranged, fmt.Sprintf("seed %d", seed))
+	}
+}
+
+func TestCountRange(t *testing.T) {
+	r := New()
+	for _, k := range []string{"2023-12-31", "2024-01-01/a", "2024-01-01/b", "2024-01-02", "2024-02-01", "2024-02-01/x", "2024-03-01"} {
+		r.Insert(k, k)
+	}
+	tests := []struct {
+		start, end string
+		want       int
+	}{
+		{"2024-01", "2024-02-01/", 4},
+		{"", "\xff", 7},
+		{"2024-01-01/b", "2024-01-02", 1},
+		{"2025", "2026", 0},
+		{"b", "a", 0},
+	}
+	for _, tc := range tests {
+		if n := r.CountRange(tc.start, tc.end); n != tc.want {
+			t.Logf("CountRange(%q, %q) should be %d, is %d", tc.start, tc.end, tc.want, n)
+			t.Fail()
+		}
+	}
+}
+
+// checkSizes reports the nodes in r whose size differs from the number of values below them.
+func checkSizes(t *testing.T, r *Radix, what string) {
+	var count func(key string, n *Radix) int
+	count = func(key string, n *Radix) int {
+		size := 0
+		if n.Value != nil {
+			size++
+		}
+		for _, child := range n.children {
+			size += count(key+child.key, child)
+		}
+		if n.size != size {
+			t.Logf("%s: node %q has size %d, holds %d values", what, key, n.size, size)
+			t.Fail()
+		}
+		return size
+	}
+	count("", r)
+}
+
+func TestSizes(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	key := func() string {
+		b := make([]byte, 1+rnd.Intn(5))
+		for j := range b {
+			b[j] = "ab/"[rnd.Intn(3)]
+		}
+		return string(b)
+	}
+	r := New()
+	r.KeepHistory(10)
+	r.Tag("start")
+	ops := []struct {
+		name string
+		op   func()
+	}{
+		{"Insert", func() { r.Insert(key(), 1) }},
+		{"Remove", func() { r.Remove(key()) }},
+		{"RemoveMany", func() { r.RemoveMany([]string{key(), key(), key()}) }},
+		{"DeleteRange", func() { r.DeleteRange(key(), key()) }},
+		{"Clear", func() { r.Namespace(key()).Clear() }},
+		{"PopMin", func() { r.PopMin() }},
+		{"Undo", func() { r.Undo(1) }},
+		{"Graft", func() {
+			sub := New()
+			sub.Insert(key(), 2)
+			sub.Insert(key(), 2)
+			r.Graft(key(), sub)
+		}},
+		{"Rollback", func() { r.Rollback("start") }},
+	}
+	for i := 0; i < 2000; i++ {
+		op := ops[rnd.Intn(len(ops))]
+		if op.name == "Rollback" && rnd.Intn(10) > 0 {
+			continue
+		}
+		op.op()
+		checkSizes(t, r, op.name)
+		if t.Failed() {
+			t.Fatalf("sizes wrong after %d operations", i+1)
+		}
+		if i%100 == 0 {
+			r.Tag("start")
+		}
+	}
+	checkSizes(t, r.Clone(), "Clone")
+	checkSizes(t, r.MapValues(func(_ string, v interface{}) interface{} {
+		if rnd.Intn(2) == 0 {
+			return nil
+		}
+		return v
+	}), "MapValues")
+	checkSizes(t, r.Filter(func(string, interface{}) bool { return rnd.Intn(2) == 0 }), "Filter")
+}
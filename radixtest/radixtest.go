@@ -0,0 +1,92 @@
+// Package radixtest generates key corpora and trees for benchmarks and tests of
+// package radix. All generators are deterministic for a given seed.
+package radixtest
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/miekg/radix"
+)
+
+var (
+	words = []string{"api", "static", "user", "users", "admin", "images", "img", "v1", "v2", "docs",
+		"blog", "post", "posts", "search", "login", "logout", "settings", "assets", "css", "js"}
+	tlds   = []string{"com", "net", "org", "nl", "de", "io", "co.uk"}
+	labels = []string{"www", "mail", "ns1", "ns2", "api", "cdn", "dev", "staging", "shop", "blog"}
+)
+
+// URLPaths returns n URL paths such as "/api/v1/users/42", built from a small vocabulary
+// so that many paths share prefixes.
+func URLPaths(n int, seed int64) []string {
+	rnd := rand.New(rand.NewSource(seed))
+	keys := make([]string, n)
+	for i := range keys {
+		depth := 1 + rnd.Intn(4)
+		parts := make([]string, depth)
+		for j := range parts {
+			parts[j] = words[rnd.Intn(len(words))]
+		}
+		if rnd.Intn(3) == 0 {
+			parts = append(parts, fmt.Sprint(rnd.Intn(1000)))
+		}
+		keys[i] = "/" + strings.Join(parts, "/")
+	}
+	return keys
+}
+
+// Hostnames returns n host names such as "mail.example42.com".
+func Hostnames(n int, seed int64) []string {
+	rnd := rand.New(rand.NewSource(seed))
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%s.example%d.%s", labels[rnd.Intn(len(labels))], rnd.Intn(n/10+1), tlds[rnd.Intn(len(tlds))])
+	}
+	return keys
+}
+
+// UUIDs returns n random version 4 UUIDs, these share hardly any prefixes.
+func UUIDs(n int, seed int64) []string {
+	rnd := rand.New(rand.NewSource(seed))
+	keys := make([]string, n)
+	for i := range keys {
+		var b [16]byte
+		rnd.Read(b[:])
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		keys[i] = fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	}
+	return keys
+}
+
+// SharedPrefix returns n keys that all start with the same prefix of length prefixLen,
+// followed by a short random suffix.
+func SharedPrefix(n, prefixLen int, seed int64) []string {
+	rnd := rand.New(rand.NewSource(seed))
+	prefix := strings.Repeat("p", prefixLen)
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%s%08x", prefix, rnd.Uint32())
+	}
+	return keys
+}
+
+// Adversarial returns n keys that force a split on every insert, when inserted in the returned
+// order: each key shares a long prefix with the key before it, but is one byte shorter.
+func Adversarial(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = strings.Repeat("a", n-i) + "b"
+	}
+	return keys
+}
+
+// Tree returns a tree holding keys, the value of each key is its index in keys.
+func Tree(keys []string) *radix.Radix {
+	r := radix.New()
+	for i, k := range keys {
+		r.Insert(k, i)
+	}
+	return r
+}
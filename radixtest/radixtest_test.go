@@ -0,0 +1,39 @@
+package radixtest
+
+import (
+	"testing"
+)
+
+func TestCorpora(t *testing.T) {
+	for name, keys := range map[string][]string{
+		"URLPaths":     URLPaths(100, 1),
+		"Hostnames":    Hostnames(100, 1),
+		"UUIDs":        UUIDs(100, 1),
+		"SharedPrefix": SharedPrefix(100, 64, 1),
+		"Adversarial":  Adversarial(100),
+	} {
+		if len(keys) != 100 {
+			t.Logf("%s should return 100 keys, returned %d", name, len(keys))
+			t.Fail()
+		}
+		r := Tree(keys)
+		for _, k := range keys {
+			if _, exact := r.Find(k); !exact {
+				t.Logf("%s: %s should be found in the tree", name, k)
+				t.Fail()
+				break
+			}
+		}
+	}
+	a, b := URLPaths(10, 7), URLPaths(10, 7)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Logf("the same seed should give the same keys")
+			t.Fail()
+		}
+	}
+	if u := UUIDs(1, 1)[0]; len(u) != 36 || u[14] != '4' {
+		t.Logf("%s is not a version 4 UUID", u)
+		t.Fail()
+	}
+}
@@ -0,0 +1,139 @@
+//go:build !tinygo
+
+package radix
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"unicode/utf8"
+)
+
+// MatchRegexp returns the nodes whose keys match re, in lexical order. The program of re is
+// run along the edges of the tree, and subtrees in which no key can match are not searched. For
+// a pattern anchored at the start of the text, as "^foo/[0-9]+/", this cuts every branch as soon
+// as it leaves the pattern; a pattern that is not anchored may match anywhere in a key and searches
+// the whole tree. re must be compiled with the Perl syntax of regexp.Compile.
+func (r *Radix) MatchRegexp(re *regexp.Regexp) []*Radix {
+	var nodes []*Radix
+	a := newAutomaton(re)
+	var match func(key string, n *Radix, s automatonState)
+	match = func(key string, n *Radix, s automatonState) {
+		if n.Value != nil && re.MatchString(key) {
+			nodes = append(nodes, n)
+		}
+		for _, k := range sortedChildren(n.children) {
+			child := n.children[k]
+			if s, ok := a.step(s, child.key); ok {
+				match(key+child.key, child, s)
+			}
+		}
+	}
+	match("", r, a.start())
+	return nodes
+}
+
+// automaton runs a regexp program one rune at a time, to find out whether a key can still
+// match once part of it is known.
+type automaton struct {
+	prog     *syntax.Prog
+	anchored bool // matches start at the beginning of the key
+}
+
+// automatonState is the state of an automaton after part of a key.
+type automatonState struct {
+	pcs     []uint32 // threads waiting for the next rune
+	prev    rune     // the last rune, -1 at the start of the key
+	pending []byte   // the start of an incomplete rune, the rest is in the next edge
+	matched bool     // a match ended before the last rune, every key continuing it matches
+}
+
+// newAutomaton returns an automaton for re, or nil if the program of re cannot be compiled.
+func newAutomaton(re *regexp.Regexp) *automaton {
+	s, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		return nil
+	}
+	prog, err := syntax.Compile(s.Simplify())
+	if err != nil {
+		return nil
+	}
+	return &automaton{prog: prog, anchored: prog.StartCond()&syntax.EmptyBeginText != 0}
+}
+
+func (a *automaton) start() automatonState {
+	if a == nil {
+		return automatonState{}
+	}
+	return automatonState{pcs: []uint32{uint32(a.prog.Start)}, prev: -1}
+}
+
+// step returns the state after b. If no key continuing with b can match, ok is false.
+func (a *automaton) step(s automatonState, b string) (next automatonState, ok bool) {
+	if a == nil || s.matched {
+		return s, true
+	}
+	pending := append(s.pending[:len(s.pending):len(s.pending)], b...)
+	pcs, prev := s.pcs, s.prev
+	for len(pending) > 0 && utf8.FullRune(pending) {
+		c, size := utf8.DecodeRune(pending)
+		pending = pending[size:]
+		if !a.anchored {
+			pcs = append(pcs[:len(pcs):len(pcs)], uint32(a.prog.Start))
+		}
+		var matched bool
+		if pcs, matched = a.next(pcs, prev, c); matched {
+			return automatonState{matched: true}, true
+		}
+		prev = c
+		if len(pcs) == 0 && a.anchored {
+			return s, false
+		}
+	}
+	return automatonState{pcs: pcs, prev: prev, pending: pending}, true
+}
+
+// next returns the threads left after c, which follows prev, is matched by the threads in pcs.
+// If one of the threads reaches a match before c, matched is true.
+func (a *automaton) next(pcs []uint32, prev, c rune) (next []uint32, matched bool) {
+	ctx := syntax.EmptyOpContext(prev, c)
+	seen := make([]bool, len(a.prog.Inst))
+	var add func(pc uint32)
+	add = func(pc uint32) {
+		if seen[pc] {
+			return
+		}
+		seen[pc] = true
+		i := &a.prog.Inst[pc]
+		switch i.Op {
+		case syntax.InstMatch:
+			matched = true
+		case syntax.InstAlt, syntax.InstAltMatch:
+			add(i.Out)
+			add(i.Arg)
+		case syntax.InstCapture, syntax.InstNop:
+			add(i.Out)
+		case syntax.InstEmptyWidth:
+			if syntax.EmptyOp(i.Arg)&^ctx == 0 {
+				add(i.Out)
+			}
+		case syntax.InstRune:
+			if i.MatchRune(c) {
+				next = append(next, i.Out)
+			}
+		case syntax.InstRune1:
+			if c == i.Rune[0] {
+				next = append(next, i.Out)
+			}
+		case syntax.InstRuneAny:
+			next = append(next, i.Out)
+		case syntax.InstRuneAnyNotNL:
+			if c != '\n' {
+				next = append(next, i.Out)
+			}
+		}
+	}
+	for _, pc := range pcs {
+		add(pc)
+	}
+	return next, matched
+}
@@ -0,0 +1,75 @@
+//go:build !tinygo
+
+package radix
+
+import (
+	"math/rand"
+	"regexp"
+	"testing"
+)
+
+func TestMatchRegexp(t *testing.T) {
+	r := New()
+	for _, k := range []string{"src/a.go", "src/b.go", "src/b.c", "doc/a.go", "src", "\xc3\xa9t\xc3\xa9", "\xc3\xaat\xc3\xa9"} {
+		r.Insert(k, k)
+	}
+	for re, want := range map[string]string{
+		`^src/.*\.go$`: "src/a.go src/b.go ",
+		`\.go$`:        "doc/a.go src/a.go src/b.go ",
+		`^src$`:        "src ",
+		`^src`:         "src src/a.go src/b.c src/b.go ",
+		`^src\b`:       "src src/a.go src/b.c src/b.go ",
+		`^(?i)SRC/b`:   "src/b.c src/b.go ",
+		`^[a-z]+/a`:    "doc/a.go src/a.go ",
+		`^été$`:        "été ",
+		`^lib/`:        "",
+	} {
+		got := ""
+		for _, n := range r.MatchRegexp(regexp.MustCompile(re)) {
+			got += n.Key() + " "
+		}
+		if got != want {
+			t.Logf("MatchRegexp(%s) should be %q, is %q", re, want, got)
+			t.Fail()
+		}
+	}
+
+	a := newAutomaton(regexp.MustCompile(`^src/[0-9]+/`))
+	if _, ok := a.step(a.start(), "src/12x"); ok {
+		t.Logf("src/12x should not lead to a match of ^src/[0-9]+/")
+		t.Fail()
+	}
+	if _, ok := a.step(a.start(), "src/12"); !ok {
+		t.Logf("src/12 may lead to a match of ^src/[0-9]+/")
+		t.Fail()
+	}
+}
+
+func TestMatchRegexpRandom(t *testing.T) {
+	patterns := []string{`^a+b`, `^(ab|ba)*$`, `^a.b`, `b$`, `^\bab\b`, `^[ab]{2}a`, `^(?i)AB`, `(?m)^b`, `^a*$`, `^é`}
+	for seed := int64(0); seed < 20; seed++ {
+		rnd := rand.New(rand.NewSource(seed))
+		r := New()
+		for i := 0; i < 200; i++ {
+			b := make([]byte, 1+rnd.Intn(6))
+			for j := range b {
+				b[j] = "ab \n\xc3\xa9"[rnd.Intn(6)]
+			}
+			r.Insert(string(b), true)
+		}
+		for _, p := range patterns {
+			re := regexp.MustCompile(p)
+			want := 0
+			r.Walk(func(key string, _ interface{}) error {
+				if re.MatchString(key) {
+					want++
+				}
+				return nil
+			})
+			if got := len(r.MatchRegexp(re)); got != want {
+				t.Logf("seed %d: MatchRegexp(%s) should find %d keys, found %d", seed, p, want, got)
+				t.Fail()
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package radix
+
+// Action is the action of a Rule.
+type Action int
+
+const (
+	Allow Action = iota // allow access
+	Deny                // deny access
+)
+
+// Rule is a rule in a Rules table.
+type Rule struct {
+	Prefix   string
+	Priority int
+	Action   Action
+}
+
+// Rules is a table of rules matched on key prefixes, for instance for path based access
+// control. The rule with the longest prefix of a key wins, when several rules have that
+// prefix the one with the highest priority wins, and when those have the same priority
+// Deny wins over Allow.
+type Rules struct {
+	tree *Radix
+}
+
+// NewRules returns an empty rule table.
+func NewRules() *Rules {
+	return &Rules{tree: New()}
+}
+
+// Add adds rule to the table.
+func (rs *Rules) Add(rule Rule) {
+	if rule.Prefix == "" {
+		rules, _ := rs.tree.Value.([]Rule)
+		rs.tree.set(append(rules, rule))
+		return
+	}
+	var rules []Rule
+	if n, exact := rs.tree.find(rule.Prefix); exact {
+		rules = n.Value.([]Rule)
+	}
+	rs.tree.Insert(rule.Prefix, append(rules, rule))
+}
+
+// Remove removes all rules with prefix from the table.
+func (rs *Rules) Remove(prefix string) {
+	if prefix == "" {
+		rs.tree.set(nil)
+		return
+	}
+	rs.tree.Remove(prefix)
+}
+
+// Match returns the rule that wins for key. If no rule matches, ok is false.
+func (rs *Rules) Match(key string) (rule Rule, ok bool) {
+	n := rs.tree
+	if key != "" {
+		n, _ = rs.tree.find(key)
+	}
+	if n == nil || n.Value == nil {
+		return Rule{}, false
+	}
+	for i, r := range n.Value.([]Rule) {
+		if i == 0 || r.Priority > rule.Priority || (r.Priority == rule.Priority && r.Action == Deny) {
+			rule = r
+		}
+	}
+	return rule, true
+}
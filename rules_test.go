@@ -0,0 +1,40 @@
+package radix
+
+import (
+	"testing"
+)
+
+func TestRules(t *testing.T) {
+	rs := NewRules()
+	rs.Add(Rule{Prefix: "", Action: Deny})
+	rs.Add(Rule{Prefix: "/public/", Action: Allow})
+	rs.Add(Rule{Prefix: "/public/secret", Action: Deny})
+	rs.Add(Rule{Prefix: "/docs/", Priority: 1, Action: Allow})
+	rs.Add(Rule{Prefix: "/docs/", Priority: 0, Action: Deny})
+	rs.Add(Rule{Prefix: "/tmp/", Priority: 2, Action: Allow})
+	rs.Add(Rule{Prefix: "/tmp/", Priority: 2, Action: Deny})
+
+	for key, want := range map[string]Action{
+		"/public/index.html": Allow,
+		"/public/secret/key": Deny,
+		"/docs/a":            Allow,
+		"/tmp/x":             Deny,
+		"/etc/passwd":        Deny,
+		"":                   Deny,
+	} {
+		rule, ok := rs.Match(key)
+		if !ok || rule.Action != want {
+			t.Logf("%q should match with action %d, got %v", key, want, rule)
+			t.Fail()
+		}
+	}
+	if rule, _ := rs.Match("/public/secret/key"); rule.Prefix != "/public/secret" {
+		t.Logf("the most specific rule should win, got %v", rule)
+		t.Fail()
+	}
+	rs.Remove("")
+	if _, ok := rs.Match("/etc/passwd"); ok {
+		t.Logf("without the default rule /etc/passwd should not match")
+		t.Fail()
+	}
+}
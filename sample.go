@@ -0,0 +1,83 @@
+package radix
+
+import (
+	"math/rand"
+)
+
+// Sampler picks random nodes from a radix tree, weighted by a user supplied function.
+// The weights of all subtrees are computed once by NewSampler and used to steer each
+// descent, so sampling costs O(depth * fanout). If the tree is modified a new Sampler
+// must be created.
+type Sampler struct {
+	root   *Radix
+	weight func(interface{}) float64
+	sums   map[*Radix]float64
+}
+
+// NewSampler returns a Sampler for r. The function weight is called for each non-nil Value
+// and must return a non-negative weight, nodes with weight 0 are never sampled.
+func NewSampler(r *Radix, weight func(interface{}) float64) *Sampler {
+	s := &Sampler{root: r, weight: weight, sums: make(map[*Radix]float64)}
+	s.sum(r)
+	return s
+}
+
+// sum computes and stores the total weight of the subtree r.
+func (s *Sampler) sum(r *Radix) float64 {
+	total := 0.0
+	if r.Value != nil {
+		total = s.weight(r.Uncompressed())
+	}
+	for _, child := range r.children {
+		total += s.sum(child)
+	}
+	s.sums[r] = total
+	return total
+}
+
+// Total returns the total weight of the tree.
+func (s *Sampler) Total() float64 { return s.sums[s.root] }
+
+// Sample returns a node picked with a probability proportional to its weight. If the total
+// weight is 0, nil is returned. If rnd is nil the default source of math/rand is used.
+func (s *Sampler) Sample(rnd *rand.Rand) *Radix {
+	total := s.Total()
+	if total <= 0 {
+		return nil
+	}
+	var u float64
+	if rnd == nil {
+		u = rand.Float64() * total
+	} else {
+		u = rnd.Float64() * total
+	}
+	r := s.root
+descend:
+	for {
+		if r.Value != nil {
+			w := s.weight(r.Uncompressed())
+			if u < w {
+				return r
+			}
+			u -= w
+		}
+		var heaviest *Radix
+		for _, k := range sortedChildren(r.children) {
+			child := r.children[k]
+			w := s.sums[child]
+			if w > 0 {
+				heaviest = child
+			}
+			if u < w {
+				r = child
+				continue descend
+			}
+			u -= w
+		}
+		if heaviest == nil {
+			return nil
+		}
+		// rounding errors, take the last child with weight.
+		r, u = heaviest, 0
+	}
+}
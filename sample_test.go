@@ -0,0 +1,30 @@
+package radix
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSample(t *testing.T) {
+	r := New()
+	r.Insert("a", 1.0)
+	r.Insert("ab", 0.0)
+	r.Insert("b", 3.0)
+	s := NewSampler(r, func(v interface{}) float64 { return v.(float64) })
+	if s.Total() != 4 {
+		t.Fatalf("Total should be 4, is %f", s.Total())
+	}
+	rnd := rand.New(rand.NewSource(1))
+	count := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		count[s.Sample(rnd).Key()]++
+	}
+	if count["ab"] != 0 {
+		t.Logf("ab has weight 0 and should never be sampled")
+		t.Fail()
+	}
+	if count["b"] < 2700 || count["b"] > 3300 {
+		t.Logf("b should be sampled about 3000 times, is %d", count["b"])
+		t.Fail()
+	}
+}
@@ -0,0 +1,79 @@
+package radix
+
+import (
+	"sort"
+)
+
+// Clone returns a copy of the tree r. The nodes are copied, the Values and Metas are not.
+// The copy does not have the settings of r, such as limits or history.
+func (r *Radix) Clone() *Radix {
+	return r.clone(nil)
+}
+
+func (r *Radix) clone(parent *Radix) *Radix {
+	c := &Radix{key: r.key, parent: parent, Value: r.Value, size: r.size}
+	if len(r.children) > 0 {
+		c.children = make(map[byte]*Radix, len(r.children))
+	}
+	if r.ext != nil {
+		ext := *r.ext
+		ext.hits = r.hits()
+		c.ext = &ext
+	}
+	for k, child := range r.children {
+		c.children[k] = child.clone(c)
+	}
+	return c
+}
+
+// Tag saves a copy of the current contents of r under name, replacing any copy already
+// saved under that name. See Rollback. r must be the root of the radix tree.
+func (r *Radix) Tag(name string) {
+	t := r.settings()
+	if t.snapshots == nil {
+		t.snapshots = make(map[string]*Radix)
+	}
+	t.snapshots[name] = r.Clone()
+}
+
+// Untag removes the copy saved under name.
+func (r *Radix) Untag(name string) {
+	if r.tree != nil {
+		delete(r.tree.snapshots, name)
+	}
+}
+
+// Snapshots returns the names of the saved copies, sorted.
+func (r *Radix) Snapshots() []string {
+	names := []string{}
+	if r.tree == nil {
+		return names
+	}
+	for name := range r.tree.snapshots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Rollback restores the contents of r to the copy saved under name. The copy is kept,
+// so it can be restored again. The history kept for Undo and Redo is dropped. If there
+// is no copy saved under name, false is returned. r must be the root of the radix tree.
+func (r *Radix) Rollback(name string) bool {
+	t := r.tree
+	if t == nil || t.snapshots[name] == nil {
+		return false
+	}
+	c := t.snapshots[name].Clone()
+	r.children = c.children
+	for _, child := range r.children {
+		child.parent = r
+	}
+	r.Value, r.ext, r.size = c.Value, c.ext, c.size
+	t.undo, t.redo = nil, nil
+	t.rev++
+	t.forgotten = t.rev // the keys removed have no tombstones, see WriteDelta
+	t.stamp(r)
+	r.recount()
+	return true
+}
@@ -0,0 +1,231 @@
+package radix
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+)
+
+// ErrFormat is returned when a snapshot can not be read.
+var ErrFormat = errors.New("radix: bad snapshot")
+
+// Codec encodes and decodes the values of a tree for snapshots.
+type Codec interface {
+	Encode(v interface{}) ([]byte, error)
+	Decode(b []byte) (interface{}, error)
+}
+
+// StringCodec is a Codec for trees holding string values. []byte values are encoded as well,
+// but decoded as strings.
+type StringCodec struct{}
+
+// Encode implements Codec.
+func (StringCodec) Encode(v interface{}) ([]byte, error) {
+	switch x := v.(type) {
+	case string:
+		return []byte(x), nil
+	case []byte:
+		return x, nil
+	}
+	return nil, errors.New("radix: StringCodec: value is not a string")
+}
+
+// Decode implements Codec.
+func (StringCodec) Decode(b []byte) (interface{}, error) { return string(b), nil }
+
+var (
+	snapshotMagic = [4]byte{'R', 'D', 'X', 'V'} // followed by the version and the flags
+	legacyMagic   = [4]byte{'R', 'D', 'X', 'S'} // version 1, followed by the flags
+)
+
+// SnapshotVersion is the version of the snapshot format written by WriteSnapshot. ReadSnapshot
+// reads snapshots of this and all earlier versions.
+//
+//	1: front coded entries, no version in the header
+//	2: the header holds the version
+const SnapshotVersion = 2
+
+const snapshotCompressed = 1 << 0 // flags: entries are compressed with flate
+
+// WriteSnapshot writes the keys and values of r to w, values are encoded with c. The keys are
+// written in lexical order and front coded: each key is stored as the length of the prefix it
+// shares with the previous key plus the rest of it. If compress is true, the entries are also
+// compressed with flate. Keys are relative to r. The snapshot starts with SnapshotVersion.
+func (r *Radix) WriteSnapshot(w io.Writer, c Codec, compress bool) error {
+	flags := byte(0)
+	if compress {
+		flags |= snapshotCompressed
+	}
+	var fw io.WriteCloser
+	if compress {
+		var err error
+		if fw, err = compressWriter(w); err != nil {
+			return err
+		}
+	}
+	if _, err := w.Write(append(snapshotMagic[:], SnapshotVersion, flags)); err != nil {
+		return err
+	}
+	if compress {
+		if err := writeEntries(fw, r.Len(), r.Walk, c); err != nil {
+			return err
+		}
+		return fw.Close()
+	}
+	return writeEntries(w, r.Len(), r.Walk, c)
+}
+
+// writeEntries writes count followed by the front coded entries produced by each, which
+// must produce count entries in lexical order. If c is nil only the keys are written.
+func writeEntries(w io.Writer, count int, each func(func(key string, value interface{}) error) error, c Codec) error {
+	bw := bufio.NewWriter(w)
+	var buf []byte
+	put := func(x uint64) {
+		buf = appendUvarint(buf[:0], x)
+		bw.Write(buf)
+	}
+	put(uint64(count))
+	prev := ""
+	err := each(func(key string, value interface{}) error {
+		shared := 0
+		for shared < len(key) && shared < len(prev) && key[shared] == prev[shared] {
+			shared++
+		}
+		put(uint64(shared))
+		put(uint64(len(key) - shared))
+		bw.WriteString(key[shared:])
+		prev = key
+		if c == nil {
+			return nil
+		}
+		value, err := plain(value)
+		if err != nil {
+			return err
+		}
+		v, err := c.Encode(value)
+		if err != nil {
+			return err
+		}
+		put(uint64(len(v)))
+		bw.Write(v)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// ReadSnapshot reads a snapshot written by WriteSnapshot and returns it as a new tree,
+// values are decoded with c. Snapshots written by earlier versions of this package are read
+// as well, for a snapshot with a version newer than SnapshotVersion an error wrapping
+// ErrFormat is returned.
+func ReadSnapshot(rd io.Reader, c Codec) (*Radix, error) {
+	var magic [4]byte
+	if _, err := io.ReadFull(rd, magic[:]); err != nil {
+		return nil, ErrFormat
+	}
+	var header [2]byte // version and flags
+	switch magic {
+	case legacyMagic:
+		header[0] = 1
+		if _, err := io.ReadFull(rd, header[1:]); err != nil {
+			return nil, ErrFormat
+		}
+	case snapshotMagic:
+		if _, err := io.ReadFull(rd, header[:]); err != nil {
+			return nil, ErrFormat
+		}
+	default:
+		return nil, ErrFormat
+	}
+	if header[0] > SnapshotVersion {
+		return nil, wrap(ErrFormat, "version "+strconv.Itoa(int(header[0]))+" is newer than "+strconv.Itoa(SnapshotVersion))
+	}
+	if header[1]&^snapshotCompressed != 0 {
+		return nil, wrap(ErrFormat, "unknown flags")
+	}
+	if header[1]&snapshotCompressed != 0 {
+		fr, err := decompressReader(rd)
+		if err != nil {
+			return nil, err
+		}
+		defer fr.Close()
+		rd = fr
+	}
+	r := New()
+	err := readEntries(bufio.NewReader(rd), c, func(key string, value interface{}) {
+		if key == "" {
+			r.setRoot(value)
+			return
+		}
+		r.Insert(key, value)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// readEntries reads the entries written by writeEntries and calls f for each of them. If c
+// is nil the entries have no values and f is called with a nil value.
+func readEntries(br *bufio.Reader, c Codec, f func(key string, value interface{})) error {
+	count, err := readUvarint(br)
+	if err != nil {
+		return ErrFormat
+	}
+	var prev []byte
+	for i := uint64(0); i < count; i++ {
+		shared, err1 := readUvarint(br)
+		n, err2 := readUvarint(br)
+		if err1 != nil || err2 != nil || shared > uint64(len(prev)) {
+			return ErrFormat
+		}
+		suffix, err := readBytes(br, n)
+		if err != nil {
+			return err
+		}
+		key := append(prev[:shared:shared], suffix...)
+		prev = key
+		if c == nil {
+			f(string(key), nil)
+			continue
+		}
+		vlen, err := readUvarint(br)
+		if err != nil {
+			return ErrFormat
+		}
+		v, err := readBytes(br, vlen)
+		if err != nil {
+			return err
+		}
+		value, err := c.Decode(v)
+		if err != nil {
+			return err
+		}
+		f(string(key), value)
+	}
+	return nil
+}
+
+// readBytes reads n bytes from br. n is read from the stream itself, so for large n the buffer
+// only grows as the data arrives: a corrupt length gives ErrFormat, not a huge allocation.
+func readBytes(br *bufio.Reader, n uint64) ([]byte, error) {
+	if n <= 1<<16 {
+		b := make([]byte, n)
+		if _, err := io.ReadFull(br, b); err != nil {
+			return nil, ErrFormat
+		}
+		return b, nil
+	}
+	if n > 1<<62 {
+		return nil, ErrFormat
+	}
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, br, int64(n)); err != nil {
+		return nil, ErrFormat
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,96 @@
+package radix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestSnapshotFormat(t *testing.T) {
+	r := New()
+	for i := 0; i < 1000; i++ {
+		k := "com.example.www/some/long/shared/path/" + string(rune('a'+i%26)) + string(rune('a'+i/26))
+		r.Insert(k, "v")
+	}
+	r.Insert("a", "")
+	for _, compress := range compressModes {
+		var buf bytes.Buffer
+		if err := r.WriteSnapshot(&buf, StringCodec{}, compress); err != nil {
+			t.Fatal(err)
+		}
+		// Front coding should make this much smaller than the keys themselves (40K).
+		if buf.Len() > 10000 {
+			t.Logf("snapshot (compress %t) should be small, is %d bytes", compress, buf.Len())
+			t.Fail()
+		}
+		s, err := ReadSnapshot(&buf, StringCodec{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Len() != r.Len() {
+			t.Fatalf("snapshot should hold %d keys, holds %d", r.Len(), s.Len())
+		}
+		r.Walk(func(key string, value interface{}) error {
+			if n, exact := s.Find(key); !exact || n.Value != value {
+				t.Fatalf("%s should be in the snapshot", key)
+			}
+			return nil
+		})
+	}
+	if _, err := ReadSnapshot(bytes.NewReader([]byte("junk")), StringCodec{}); !errors.Is(err, ErrFormat) {
+		t.Logf("reading junk should fail with ErrFormat, got %v", err)
+		t.Fail()
+	}
+	bad := New()
+	bad.Insert("x", 1)
+	if err := bad.WriteSnapshot(&bytes.Buffer{}, StringCodec{}, false); err == nil {
+		t.Logf("StringCodec should not encode an int")
+		t.Fail()
+	}
+}
+
+func TestSnapshotVersion(t *testing.T) {
+	// A version 1 snapshot holding "a" -> "1" and "ab" -> "2".
+	legacy := []byte{'R', 'D', 'X', 'S', 0, 2, 0, 1, 'a', 1, '1', 1, 1, 'b', 1, '2'}
+	r, err := ReadSnapshot(bytes.NewReader(legacy), StringCodec{})
+	if err != nil {
+		t.Fatalf("version 1 snapshot should be read: %s", err)
+	}
+	if n, exact := r.Find("ab"); !exact || n.Value != "2" || r.Len() != 2 {
+		t.Logf("version 1 snapshot should hold a and ab")
+		t.Fail()
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteSnapshot(&buf, StringCodec{}, false); err != nil {
+		t.Fatal(err)
+	}
+	if b := buf.Bytes(); string(b[:4]) != "RDXV" || b[4] != SnapshotVersion {
+		t.Logf("snapshot should start with RDXV and version %d, starts with %q", SnapshotVersion, b[:5])
+		t.Fail()
+	}
+
+	newer := append([]byte{}, buf.Bytes()...)
+	newer[4] = SnapshotVersion + 1
+	if _, err := ReadSnapshot(bytes.NewReader(newer), StringCodec{}); !errors.Is(err, ErrFormat) {
+		t.Logf("newer snapshot should fail with ErrFormat, got %v", err)
+		t.Fail()
+	}
+}
+
+func TestSnapshotCorrupt(t *testing.T) {
+	huge := func(b []byte) []byte { return binary.AppendUvarint(b, 1<<62-1) }
+	header := []byte{'R', 'D', 'X', 'V', SnapshotVersion, 0, 1} // one entry
+	for name, b := range map[string][]byte{
+		"key length":   huge(append(append([]byte{}, header...), 0)),
+		"value length": huge(append(append([]byte{}, header...), 0, 1, 'a')),
+		"shared":       append(append([]byte{}, header...), 5, 1, 'a'),
+		"truncated":    append(append([]byte{}, header...), 0, 200, 'a'),
+	} {
+		if _, err := ReadSnapshot(bytes.NewReader(b), StringCodec{}); !errors.Is(err, ErrFormat) {
+			t.Logf("%s: corrupt snapshot should fail with ErrFormat, got %v", name, err)
+			t.Fail()
+		}
+	}
+}
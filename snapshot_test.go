@@ -0,0 +1,54 @@
+package radix
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	r := radixtree()
+	c := r.Clone()
+	r.Remove("tester")
+	r.Insert("toast", "b")
+	if c.Len() != 4 {
+		t.Logf("the clone should not change, Len is %d", c.Len())
+		t.Fail()
+	}
+	if n, exact := c.Find("tester"); !exact || n.Up().Key() != "test" {
+		t.Logf("tester should be found in the clone, below test")
+		t.Fail()
+	}
+}
+
+func TestTagRollback(t *testing.T) {
+	r := radixtree()
+	r.Tag("yesterday")
+	r.Remove("test")
+	r.Insert("toast", "b")
+	r.Tag("today")
+	if fmt.Sprint(r.Snapshots()) != "[today yesterday]" {
+		t.Logf("Snapshots should be [today yesterday], are %v", r.Snapshots())
+		t.Fail()
+	}
+	if !r.Rollback("yesterday") {
+		t.Fatalf("Rollback(yesterday) should succeed")
+	}
+	if _, exact := r.Find("test"); !exact || r.Len() != 4 {
+		t.Logf("test should be back after Rollback")
+		t.Fail()
+	}
+	if _, exact := r.Find("toast"); exact {
+		t.Logf("toast should be gone after Rollback")
+		t.Fail()
+	}
+	r.Insert("x", "x")
+	r.Rollback("yesterday")
+	if r.Len() != 4 {
+		t.Logf("a snapshot should be restorable more than once")
+		t.Fail()
+	}
+	if r.Rollback("tomorrow") {
+		t.Logf("Rollback to an unknown name should fail")
+		t.Fail()
+	}
+}
@@ -0,0 +1,47 @@
+package radix
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Swapper holds the current version of a tree that is replaced as a whole, for instance
+// when data is reloaded. Readers call Load and use the tree they get without locking, writers
+// build a new tree with Rebuild, which is then published atomically. A published tree must
+// not be modified.
+type Swapper struct {
+	current atomic.Value // *Radix
+	mu      sync.Mutex   // serializes Rebuild
+}
+
+// NewSwapper returns a Swapper holding r. If r is nil, an empty tree is used.
+func NewSwapper(r *Radix) *Swapper {
+	if r == nil {
+		r = New()
+	}
+	s := &Swapper{}
+	s.current.Store(r)
+	return s
+}
+
+// Load returns the current tree.
+func (s *Swapper) Load() *Radix {
+	return s.current.Load().(*Radix)
+}
+
+// Store publishes r as the current tree and returns the previous one.
+func (s *Swapper) Store(r *Radix) *Radix {
+	return s.current.Swap(r).(*Radix)
+}
+
+// Rebuild calls build with a new, empty tree and publishes that tree when build returns.
+// Readers keep seeing the previous tree until then. Concurrent calls to Rebuild are serialized.
+// The new tree is returned.
+func (s *Swapper) Rebuild(build func(*Radix)) *Radix {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := New()
+	build(r)
+	s.current.Store(r)
+	return r
+}
@@ -0,0 +1,39 @@
+package radix
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSwapper(t *testing.T) {
+	s := NewSwapper(nil)
+	if s.Load().Len() != 0 {
+		t.Fatalf("a new Swapper should hold an empty tree")
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if l := s.Load().Len(); l != 0 && l != 2 {
+					t.Errorf("readers should see a complete tree, saw %d keys", l)
+				}
+				s.Load().Find("a") // readers do not write to the tree
+			}
+		}()
+	}
+	r := s.Rebuild(func(r *Radix) {
+		r.Insert("a", 1)
+		r.Insert("b", 2)
+	})
+	wg.Wait()
+	if s.Load() != r || r.Len() != 2 {
+		t.Logf("Rebuild should publish the new tree")
+		t.Fail()
+	}
+	if old := s.Store(New()); old != r {
+		t.Logf("Store should return the previous tree")
+		t.Fail()
+	}
+}
@@ -0,0 +1,170 @@
+package radix
+
+import (
+	"sort"
+	"strings"
+)
+
+// Tokenizer splits a key into tokens, see TokenTree.
+type Tokenizer func(key string) []string
+
+// Words is a Tokenizer that splits a key into words separated by white space.
+func Words(key string) []string { return strings.Fields(key) }
+
+// Segments returns a Tokenizer that splits a key into the segments separated by sep, empty
+// segments are dropped: Segments("/") splits "/usr/local/" into "usr" and "local".
+func Segments(sep string) Tokenizer {
+	return func(key string) []string {
+		var tokens []string
+		for _, s := range strings.Split(key, sep) {
+			if s != "" {
+				tokens = append(tokens, s)
+			}
+		}
+		return tokens
+	}
+}
+
+// Labels is a Tokenizer that splits a domain name into its labels, starting with the top level
+// domain: "www.example.org." is split into "org", "example" and "www".
+func Labels(key string) []string {
+	tokens := Segments(".")(key)
+	for i, j := 0, len(tokens)-1; i < j; i, j = i+1, j-1 {
+		tokens[i], tokens[j] = tokens[j], tokens[i]
+	}
+	return tokens
+}
+
+// TokenTree is a tree that branches per token instead of per byte: keys are split into tokens
+// by a Tokenizer and keys with the same tokens are the same key.
+type TokenTree struct {
+	root  *tokenNode
+	split Tokenizer
+	len   int
+}
+
+type tokenNode struct {
+	children map[string]*tokenNode
+	parent   *tokenNode
+	token    string
+	value    interface{}
+}
+
+// NewTokenTree returns an empty TokenTree that splits keys with split.
+func NewTokenTree(split Tokenizer) *TokenTree {
+	return &TokenTree{root: &tokenNode{children: make(map[string]*tokenNode)}, split: split}
+}
+
+// Len returns the number of keys in the tree.
+func (t *TokenTree) Len() int { return t.len }
+
+// Insert inserts value under key. A nil value removes key.
+func (t *TokenTree) Insert(key string, value interface{}) {
+	if value == nil {
+		t.Remove(key)
+		return
+	}
+	n := t.root
+	for _, tok := range t.split(key) {
+		child, ok := n.children[tok]
+		if !ok {
+			child = &tokenNode{children: make(map[string]*tokenNode), parent: n, token: tok}
+			n.children[tok] = child
+		}
+		n = child
+	}
+	if n.value == nil {
+		t.len++
+	}
+	n.value = value
+}
+
+// Find returns the value stored under key, or nil.
+func (t *TokenTree) Find(key string) interface{} {
+	if n := t.node(t.split(key)); n != nil {
+		return n.value
+	}
+	return nil
+}
+
+// LongestPrefix returns the value of the key with the most tokens that are a prefix of the tokens
+// of key, and the number of tokens matched. It returns nil and 0 when there is no such key.
+func (t *TokenTree) LongestPrefix(key string) (interface{}, int) {
+	n := t.root
+	value, matched := n.value, 0
+	for i, tok := range t.split(key) {
+		child, ok := n.children[tok]
+		if !ok {
+			break
+		}
+		n = child
+		if n.value != nil {
+			value, matched = n.value, i+1
+		}
+	}
+	return value, matched
+}
+
+// Remove removes key and returns its value, or nil if key was not present.
+func (t *TokenTree) Remove(key string) interface{} {
+	n := t.node(t.split(key))
+	if n == nil || n.value == nil {
+		return nil
+	}
+	old := n.value
+	n.value = nil
+	t.len--
+	for n.parent != nil && n.value == nil && len(n.children) == 0 {
+		delete(n.parent.children, n.token)
+		n = n.parent
+	}
+	return old
+}
+
+// Next returns the tokens that follow the tokens of key in the stored keys, sorted. This can be
+// used to suggest the next word or path segment while a key is typed.
+func (t *TokenTree) Next(key string) []string {
+	n := t.node(t.split(key))
+	if n == nil {
+		return nil
+	}
+	next := make([]string, 0, len(n.children))
+	for tok := range n.children {
+		next = append(next, tok)
+	}
+	sort.Strings(next)
+	return next
+}
+
+// Do calls f for the tokens of each key in t and its value, in lexical order of the tokens.
+// tokens is reused between calls.
+func (t *TokenTree) Do(f func(tokens []string, value interface{})) {
+	t.root.do(nil, f)
+}
+
+func (n *tokenNode) do(tokens []string, f func([]string, interface{})) {
+	if n.value != nil {
+		f(tokens, n.value)
+	}
+	keys := make([]string, 0, len(n.children))
+	for tok := range n.children {
+		keys = append(keys, tok)
+	}
+	sort.Strings(keys)
+	for _, tok := range keys {
+		n.children[tok].do(append(tokens, tok), f)
+	}
+}
+
+// node returns the node for tokens, or nil.
+func (t *TokenTree) node(tokens []string) *tokenNode {
+	n := t.root
+	for _, tok := range tokens {
+		child, ok := n.children[tok]
+		if !ok {
+			return nil
+		}
+		n = child
+	}
+	return n
+}
@@ -0,0 +1,50 @@
+package radix
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenTree(t *testing.T) {
+	tt := NewTokenTree(Words)
+	for _, k := range []string{"new york", "new york city", "new jersey", "newark"} {
+		tt.Insert(k, k)
+	}
+	if tt.Len() != 4 {
+		t.Logf("tree should hold 4 keys, holds %d", tt.Len())
+		t.Fail()
+	}
+	if v := tt.Find("new   york"); v != "new york" {
+		t.Logf("keys with the same words should be the same key, found %v", v)
+		t.Fail()
+	}
+	if next := strings.Join(tt.Next("new"), " "); next != "jersey york" {
+		t.Logf("words after new should be jersey york, are %s", next)
+		t.Fail()
+	}
+	if v, n := tt.LongestPrefix("new york state"); v != "new york" || n != 2 {
+		t.Logf("longest prefix of new york state should be new york, is %v (%d tokens)", v, n)
+		t.Fail()
+	}
+	if tt.Remove("new york city") != "new york city" || tt.Find("new york city") != nil || tt.Len() != 3 {
+		t.Logf("new york city should be removed")
+		t.Fail()
+	}
+	keys := ""
+	tt.Do(func(tokens []string, _ interface{}) { keys += strings.Join(tokens, "_") + " " })
+	if keys != "new_jersey new_york newark " {
+		t.Logf("Do should visit new_jersey new_york newark, visited %s", keys)
+		t.Fail()
+	}
+}
+
+func TestTokenizers(t *testing.T) {
+	if s := strings.Join(Segments("/")("/usr//local/"), " "); s != "usr local" {
+		t.Logf("Segments should split into usr local, got %s", s)
+		t.Fail()
+	}
+	if s := strings.Join(Labels("www.example.org."), " "); s != "org example www" {
+		t.Logf("Labels should split into org example www, got %s", s)
+		t.Fail()
+	}
+}
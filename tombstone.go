@@ -0,0 +1,77 @@
+package radix
+
+import (
+	"time"
+)
+
+// Tombstone records the removal of a key.
+type Tombstone struct {
+	Key  string
+	Rev  uint64    // revision of the tree after the removal
+	Time time.Time // time of the removal
+}
+
+// KeepTombstones makes the tree r remember removed keys for the duration window, so replicas
+// that missed the removal can learn about it, see Tombstones. A zero window stops keeping
+// tombstones and drops the ones kept so far. r must be the root of the radix tree.
+func (r *Radix) KeepTombstones(window time.Duration) {
+	t := r.settings()
+	if t.window == 0 || window == 0 {
+		t.forgotten = t.rev
+	}
+	t.window = window
+	if window == 0 {
+		t.tombstones = nil
+	}
+	if t.now == nil {
+		t.now = time.Now
+	}
+}
+
+// Tombstones returns the tombstones of keys removed after revision rev, oldest first.
+// Keys that have been inserted again since their removal are not returned.
+// r must be the root of the radix tree.
+func (r *Radix) Tombstones(rev uint64) []Tombstone {
+	t := r.tree
+	if t == nil || t.window == 0 {
+		return nil
+	}
+	t.expire()
+	latest := make(map[string]uint64)
+	for _, ts := range t.tombstones {
+		latest[ts.Key] = ts.Rev
+	}
+	var ret []Tombstone
+	for _, ts := range t.tombstones {
+		if ts.Rev <= rev || latest[ts.Key] != ts.Rev {
+			continue
+		}
+		if _, exact := r.find(ts.Key); exact {
+			continue
+		}
+		ret = append(ret, ts)
+	}
+	return ret
+}
+
+// bury records a tombstone for key, if tombstones are kept.
+func (t *tree) bury(key string) {
+	if t.window == 0 {
+		return
+	}
+	t.tombstones = append(t.tombstones, Tombstone{Key: key, Rev: t.rev, Time: t.now()})
+	t.expire()
+}
+
+// expire drops the tombstones older than the window.
+func (t *tree) expire() {
+	cutoff := t.now().Add(-t.window)
+	i := 0
+	for i < len(t.tombstones) && t.tombstones[i].Time.Before(cutoff) {
+		i++
+	}
+	if i > 0 {
+		t.forgotten = t.tombstones[i-1].Rev
+	}
+	t.tombstones = t.tombstones[i:]
+}
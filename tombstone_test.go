@@ -0,0 +1,34 @@
+package radix
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTombstones(t *testing.T) {
+	r := radixtree()
+	r.KeepTombstones(time.Minute)
+	now := time.Now()
+	r.tree.now = func() time.Time { return now }
+
+	r.Remove("tester")
+	rev := r.Revision()
+	r.Remove("team")
+	r.Remove("notthere")
+	r.Remove("test")
+	r.Insert("test", "again")
+
+	ts := r.Tombstones(0)
+	if len(ts) != 2 || ts[0].Key != "tester" || ts[1].Key != "team" {
+		t.Fatalf("there should be tombstones for tester and team, got %v", ts)
+	}
+	if ts := r.Tombstones(rev); len(ts) != 1 || ts[0].Key != "team" {
+		t.Logf("only team should be removed after revision %d, got %v", rev, ts)
+		t.Fail()
+	}
+	now = now.Add(2 * time.Minute)
+	if ts := r.Tombstones(0); len(ts) != 0 {
+		t.Logf("tombstones should expire, got %v", ts)
+		t.Fail()
+	}
+}
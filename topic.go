@@ -0,0 +1,89 @@
+package radix
+
+import (
+	"sort"
+	"strings"
+)
+
+// MatchTopic returns the nodes whose keys are MQTT topic filters matching topic, sorted by key.
+// Topics and filters are made of levels separated by '/'. In a filter a '+' level matches any
+// one level and a '#' level, which must be the last, matches any number of levels, including
+// none: "a/#" matches "a", "a/b" and "a/b/c". As in MQTT, wildcards at the first level do not
+// match topics starting with '$'. r must be the root of the radix tree.
+func (r *Radix) MatchTopic(topic string) []*Radix {
+	var nodes []*Radix
+	position{r, len(r.key)}.matchTopic(topic, strings.HasPrefix(topic, "$"), &nodes)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Key() < nodes[j].Key() })
+	return nodes
+}
+
+// position is a position in the tree: the first i bytes of the key of n have been matched.
+type position struct {
+	n *Radix
+	i int
+}
+
+// step returns the position after matching c, ok is false if the tree has no such key.
+func (p position) step(c byte) (position, bool) {
+	if p.i < len(p.n.key) {
+		return position{p.n, p.i + 1}, p.n.key[p.i] == c
+	}
+	child, ok := p.n.children[c]
+	if !ok {
+		return p, false
+	}
+	return position{child, 1}, true
+}
+
+// steps returns the position after matching s.
+func (p position) steps(s string) (position, bool) {
+	ok := true
+	for i := 0; i < len(s) && ok; i++ {
+		p, ok = p.step(s[i])
+	}
+	return p, ok
+}
+
+// node returns the node whose key ends at p, or nil.
+func (p position) node() *Radix {
+	if p.i == len(p.n.key) && p.n.Value != nil {
+		return p.n
+	}
+	return nil
+}
+
+// matchTopic adds the filters matching topic from position p, which is at the start of a level.
+func (p position) matchTopic(topic string, noWildcard bool, nodes *[]*Radix) {
+	level, rest, more := strings.Cut(topic, "/")
+	if !noWildcard {
+		if q, ok := p.step('#'); ok && q.node() != nil {
+			*nodes = append(*nodes, q.n)
+		}
+		if q, ok := p.step('+'); ok {
+			q.matchLevel(rest, more, nodes)
+		}
+	}
+	if q, ok := p.steps(level); ok {
+		q.matchLevel(rest, more, nodes)
+	}
+}
+
+// matchLevel continues matchTopic from position p, which is at the end of a level.
+func (p position) matchLevel(rest string, more bool, nodes *[]*Radix) {
+	q, ok := p.step('/')
+	if more {
+		if ok {
+			q.matchTopic(rest, false, nodes)
+		}
+		return
+	}
+	if n := p.node(); n != nil {
+		*nodes = append(*nodes, n)
+	}
+	// "a/#" also matches "a"
+	if ok {
+		if q, ok = q.step('#'); ok && q.node() != nil {
+			*nodes = append(*nodes, q.n)
+		}
+	}
+}
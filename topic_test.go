@@ -0,0 +1,34 @@
+package radix
+
+import (
+	"testing"
+)
+
+func TestMatchTopic(t *testing.T) {
+	r := New()
+	for _, f := range []string{"sport/tennis/player1", "sport/tennis/+", "sport/#", "sport/+/player1",
+		"+/tennis/#", "#", "sport/tennis", "finance/#", "$SYS/#", "+/monitor"} {
+		r.Insert(f, f)
+	}
+	tests := []struct {
+		topic string
+		want  string
+	}{
+		{"sport/tennis/player1", "# +/tennis/# sport/# sport/+/player1 sport/tennis/+ sport/tennis/player1 "},
+		{"sport/tennis", "# +/tennis/# sport/# sport/tennis "},
+		{"sport", "# sport/# "},
+		{"finance", "# finance/# "},
+		{"$SYS/monitor", "$SYS/# "},
+		{"news/monitor", "# +/monitor "},
+	}
+	for _, tc := range tests {
+		got := ""
+		for _, n := range r.MatchTopic(tc.topic) {
+			got += n.Key() + " "
+		}
+		if got != tc.want {
+			t.Logf("%s should match %q, matched %q", tc.topic, tc.want, got)
+			t.Fail()
+		}
+	}
+}
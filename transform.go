@@ -0,0 +1,87 @@
+package radix
+
+// MapValues returns a new tree holding the keys of r with their values replaced by the result
+// of fn, called once for each key in r. If fn returns nil the key is left out. The new tree is
+// built in a single pass over r, copying its structure; Metas are copied as well. Keys are
+// relative to r.
+func (r *Radix) MapValues(fn func(key string, v interface{}) interface{}) *Radix {
+	var drop []string
+	m := r.mapValues(nil, "", fn, &drop)
+	m.key = ""
+	for _, key := range drop {
+		m.Remove(key)
+	}
+	return m
+}
+
+func (r *Radix) mapValues(parent *Radix, key string, fn func(string, interface{}) interface{}, drop *[]string) *Radix {
+	c := &Radix{key: r.key, parent: parent}
+	if len(r.children) > 0 {
+		c.children = make(map[byte]*Radix, len(r.children))
+	}
+	c.setMeta(r.Meta())
+	if r.Value != nil {
+		if c.Value = fn(key, r.Uncompressed()); c.Value == nil && key != "" {
+			*drop = append(*drop, key)
+		}
+	}
+	for k, child := range r.children {
+		c.children[k] = child.mapValues(c, key+child.key, fn, drop)
+	}
+	c.resize()
+	return c
+}
+
+// Filter returns a new tree holding the keys of r, and their values, for which pred returns
+// true. The new tree is built in a single pass over r. Only the nodes needed for the kept keys
+// are copied: subtrees without them are skipped and the nodes left with a single child are
+// merged with it. Keys are relative to r.
+func (r *Radix) Filter(pred func(key string, v interface{}) bool) *Radix {
+	f := r.filter(nil, "", pred)
+	if f == nil {
+		return New()
+	}
+	f.key = ""
+	return f
+}
+
+// filter returns the filtered copy of r, or nil if no key in r is kept.
+func (r *Radix) filter(parent *Radix, key string, pred func(string, interface{}) bool) *Radix {
+	c := &Radix{key: r.key, parent: parent}
+	if r.Value != nil && pred(key, r.Uncompressed()) {
+		c.Value = r.Value
+		c.setMeta(r.Meta())
+	}
+	for _, child := range r.children {
+		if fc := child.filter(c, key+child.key, pred); fc != nil {
+			c.addChild(fc)
+		}
+	}
+	c.resize()
+	if c.Value != nil || parent == nil {
+		return c
+	}
+	switch len(c.children) {
+	case 0:
+		return nil
+	case 1:
+		for _, child := range c.children {
+			child.key = c.key + child.key
+			child.parent = parent
+			return child
+		}
+	}
+	return c
+}
+
+// Fold calls fn for each key in r and its value, in lexical order, passing the result of the
+// previous call, or init for the first one. It returns the result of the last call, or init if
+// r holds no keys. fn must not modify the tree. Keys are relative to r.
+func (r *Radix) Fold(init interface{}, fn func(acc interface{}, key string, v interface{}) interface{}) interface{} {
+	acc := init
+	r.ordered("", func(key string, n *Radix) bool {
+		acc = fn(acc, key, n.Uncompressed())
+		return true
+	})
+	return acc
+}
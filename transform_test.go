@@ -0,0 +1,78 @@
+package radix
+
+import (
+	"testing"
+)
+
+func TestMapValues(t *testing.T) {
+	r := New()
+	r.Insert("test", 1)
+	r.Insert("team", 2)
+	r.Insert("toast", 3)
+	m := r.MapValues(func(key string, v interface{}) interface{} {
+		if key == "team" {
+			return nil
+		}
+		return v.(int) * 10
+	})
+	if m.Len() != 2 {
+		t.Fatalf("mapped tree should hold 2 keys, holds %d", m.Len())
+	}
+	for key, want := range map[string]int{"test": 10, "toast": 30} {
+		if n, exact := m.Find(key); !exact || n.Value != want {
+			t.Logf("%s should be mapped to %d", key, want)
+			t.Fail()
+		}
+	}
+	if _, exact := m.Find("team"); exact {
+		t.Logf("team should be left out")
+		t.Fail()
+	}
+	if n, _ := r.Find("test"); n.Value != 1 {
+		t.Logf("MapValues should not change r")
+		t.Fail()
+	}
+}
+
+func TestFilter(t *testing.T) {
+	r := New()
+	for i, k := range []string{"test", "tester", "team", "toast", "slow"} {
+		r.Insert(k, i)
+	}
+	f := r.Filter(func(key string, v interface{}) bool { return key != "test" && key != "team" })
+	if f.Len() != 3 {
+		t.Fatalf("filtered tree should hold 3 keys, holds %d", f.Len())
+	}
+	for _, k := range []string{"tester", "toast", "slow"} {
+		if n, exact := f.Find(k); !exact || n.Key() != k {
+			t.Logf("%s should be kept", k)
+			t.Fail()
+		}
+	}
+	// te and test are merged into tester
+	if n, _ := f.Find("tester"); n.parent.key != "t" || n.key != "ester" {
+		t.Logf("single child nodes should be merged, tester is stored as %q under %q", n.key, n.parent.key)
+		t.Fail()
+	}
+	if e := r.Filter(func(string, interface{}) bool { return false }); e.Len() != 0 {
+		t.Logf("filtering everything out should give an empty tree")
+		t.Fail()
+	}
+}
+
+func TestFold(t *testing.T) {
+	r := New()
+	r.Insert("b", 2)
+	r.Insert("a", 1)
+	r.Insert("c", 3)
+	sum := r.Fold(0, func(acc interface{}, _ string, v interface{}) interface{} { return acc.(int) + v.(int) })
+	if sum != 6 {
+		t.Logf("sum should be 6, is %v", sum)
+		t.Fail()
+	}
+	keys := r.Fold("", func(acc interface{}, key string, _ interface{}) interface{} { return acc.(string) + key })
+	if keys != "abc" {
+		t.Logf("Fold should visit the keys in lexical order, visited %v", keys)
+		t.Fail()
+	}
+}
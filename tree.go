@@ -0,0 +1,266 @@
+package radix
+
+import (
+	"container/list"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// ErrLimit is returned when an insert would exceed the limits of the tree.
+var ErrLimit = errors.New("radix: limit exceeded")
+
+// wrapError adds detail to one of the errors of this package. It is used instead of
+// fmt.Errorf to keep fmt out of the core of the package, see the package documentation.
+type wrapError struct {
+	err    error
+	detail string
+}
+
+func wrap(err error, detail string) error { return &wrapError{err, detail} }
+
+func (e *wrapError) Error() string { return e.err.Error() + ": " + e.detail }
+func (e *wrapError) Unwrap() error { return e.err }
+
+// tree holds the settings and bookkeeping that apply to a whole tree. It is only
+// set on the root node, and only when one of the settings is used.
+type tree struct {
+	limits Limits
+	keys   int    // number of nodes with a non-nil Value
+	nodes  int    // number of nodes, not counting the root
+	rev    uint64 // incremented on each Insert and Remove
+
+	tombstones []Tombstone // ordered by Rev, see KeepTombstones
+	window     time.Duration
+	now        func() time.Time
+	forgotten  uint64 // removals up to this revision may have no tombstone
+
+	undo, redo []change // see KeepHistory
+	history    int      // maximum length of undo, -1 when history is not kept
+	replaying  bool     // set during Undo and Redo
+
+	feed     func(Record) // see OnChange
+	seq      uint64       // sequence number of the last record passed to feed
+	batching bool         // set during Apply, its changes are passed to feed when all succeed
+
+	epoch uint64 // incremented when nodes may have been removed, see Finger
+
+	snapshots map[string]*Radix // see Tag
+
+	order    *list.List               // keys in insertion order, see KeepInsertionOrder
+	elements map[string]*list.Element // the elements of order
+
+	access bool // see TrackAccess
+
+	modifications bool   // see TrackModifications
+	tracked       uint64 // revision at which modifications started to be tracked
+
+	quotas *Radix // prefix to *quota, see SetQuota
+
+	arena []Radix // preallocated nodes, see NewWithSize
+	bare  bool    // only arena is set: Insert and Remove skip the bookkeeping, see settings
+
+	mounts *Radix // alias to *mount, see Mount
+
+	defaults *Radix // prefix to default value, see SetDefault
+
+	compressor      Compressor // see CompressValues
+	threshold       int
+	raw, compressed int64 // sizes of the compressed values
+}
+
+// settings returns the tree settings of r, creating them when needed. r must be the root.
+func (r *Radix) settings() *tree {
+	switch {
+	case r.tree == nil:
+		r.tree = &tree{history: -1}
+		r.recount()
+	case r.tree.bare:
+		r.tree.bare = false
+		r.recount()
+	}
+	return r.tree
+}
+
+// Revision returns the revision of the tree r, it is incremented on each Insert and Remove.
+// Revisions are only counted for trees that have settings, such as limits (NewWithLimits) or
+// tombstones (KeepTombstones). r must be the root of the radix tree.
+func (r *Radix) Revision() uint64 {
+	if r.tree == nil || r.tree.bare {
+		return 0
+	}
+	return r.tree.rev
+}
+
+// Limits restricts the size of a tree. A zero field means no limit.
+type Limits struct {
+	MaxKeyLen int // maximum length of a key
+	MaxKeys   int // maximum number of keys with a non-nil Value
+	MaxNodes  int // maximum number of nodes, not counting the root
+}
+
+// NewWithLimits returns an initialized radix tree that enforces the limits l on Insert.
+func NewWithLimits(l Limits) *Radix {
+	r := New()
+	r.tree = &tree{limits: l, history: -1}
+	return r
+}
+
+// NewWithSize returns an initialized radix tree with room for about n nodes allocated up
+// front, this reduces the number of allocations when n keys are inserted. Nodes beyond these
+// are allocated one by one. The preallocated nodes are allocated as a single block that is only
+// freed when all of its nodes are no longer used, even when most of them have been removed.
+// Until one of the settings is used, Insert and Remove work as fast as they do on a tree
+// returned by New.
+func NewWithSize(n int) *Radix {
+	r := New()
+	r.tree = &tree{history: -1, arena: make([]Radix, n), bare: true}
+	return r
+}
+
+// newNode returns a new node without a value with key and parent, taking it from the arena of t
+// when there is one. t may be nil.
+func (t *tree) newNode(key string, parent *Radix) *Radix {
+	var n *Radix
+	if t != nil && len(t.arena) > 0 {
+		n, t.arena = &t.arena[0], t.arena[1:]
+	} else {
+		n = new(Radix)
+	}
+	*n = Radix{key: key, parent: parent}
+	if t != nil && t.access {
+		n.ext = new(extra)
+	}
+	return n
+}
+
+// setRoot sets the Value of the root r, which holds the value of the empty key. Insert and
+// Remove do not accept the empty key.
+func (r *Radix) setRoot(value interface{}) {
+	if t := r.tree; t != nil {
+		value = t.compress(value)
+		t.account(r.Value, value)
+		switch {
+		case r.Value == nil && value != nil:
+			t.keys++
+		case r.Value != nil && value == nil:
+			t.keys--
+		}
+	}
+	r.set(value)
+}
+
+// changeRoot works like setRoot, but counts as a change of the empty key for the revision, the
+// history and the change feed.
+func (r *Radix) changeRoot(value interface{}) {
+	old := r.Value
+	r.setRoot(value)
+	if t := r.tree; t != nil {
+		t.rev++
+		t.record("", old, r.Value)
+	}
+}
+
+// TryInsert works like Insert, but returns an error wrapping ErrLimit if the insert
+// would exceed the limits of the tree. In that case the tree is not modified.
+// r must be the root of the radix tree.
+func (r *Radix) TryInsert(key string, value interface{}) (*Radix, error) {
+	t := r.tree
+	if t == nil || t.bare {
+		return r.insert(key, value, t), nil
+	}
+	l := t.limits
+	if l.MaxKeyLen > 0 && len(key) > l.MaxKeyLen {
+		return nil, wrap(ErrLimit, "key length "+strconv.Itoa(len(key))+" > "+strconv.Itoa(l.MaxKeyLen))
+	}
+	var old interface{}
+	if n := r.node(key); n != nil {
+		old = n.Value
+	}
+	value = t.compress(value)
+	keys := 0
+	if old == nil && value != nil {
+		keys = 1
+	} else if old != nil && value == nil {
+		keys = -1
+	}
+	nodes := r.newNodes(key)
+	if l.MaxKeys > 0 && keys > 0 && t.keys+keys > l.MaxKeys {
+		return nil, wrap(ErrLimit, "more than "+strconv.Itoa(l.MaxKeys)+" keys")
+	}
+	if l.MaxNodes > 0 && nodes > 0 && t.nodes+nodes > l.MaxNodes {
+		return nil, wrap(ErrLimit, "more than "+strconv.Itoa(l.MaxNodes)+" nodes")
+	}
+	if keys > 0 {
+		if err := t.checkQuota(key); err != nil {
+			return nil, err
+		}
+	}
+	n := r.insert(key, value, t)
+	t.keys += keys
+	t.nodes += nodes
+	t.rev++
+	t.modified(n)
+	switch {
+	case keys > 0:
+		t.added(key)
+		t.quota(key, 1)
+	case keys < 0:
+		t.bury(key)
+		t.dropped(key)
+		t.quota(key, -1)
+	}
+	t.account(old, value)
+	t.record(key, old, value)
+	return n, nil
+}
+
+// newNodes returns the number of nodes insert creates when key is inserted in r.
+func (r *Radix) newNodes(key string) int {
+	for key != "" {
+		child, ok := r.children[key[0]]
+		if !ok {
+			return 1
+		}
+		_, i := longestCommonPrefix(key, child.key)
+		if i < len(child.key) {
+			if i == len(key) {
+				return 1 // child is split, the new node holds key
+			}
+			return 2 // child is split and a new leaf holds the rest of key
+		}
+		key = key[i:]
+		r = child
+	}
+	return 0
+}
+
+// recount recomputes the number of keys and nodes of the tree r. It must be called
+// after the structure of the tree is changed without going through Insert or Remove.
+func (r *Radix) recount() {
+	t := r.tree
+	if t == nil {
+		return
+	}
+	t.keys, t.nodes = 0, 0
+	t.raw, t.compressed = 0, 0
+	t.epoch++
+	var count func(*Radix)
+	count = func(n *Radix) {
+		if n.Value != nil {
+			t.keys++
+			t.account(nil, n.Value)
+		}
+		if t.access {
+			n.extra()
+		}
+		t.nodes++
+		for _, child := range n.children {
+			count(child)
+		}
+	}
+	count(r)
+	t.nodes-- // the root
+	r.reorder()
+	r.requota()
+}
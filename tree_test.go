@@ -0,0 +1,119 @@
+package radix
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+	r := NewWithLimits(Limits{MaxKeyLen: 8, MaxKeys: 3, MaxNodes: 4})
+	if _, err := r.TryInsert("toolongkey", "a"); !errors.Is(err, ErrLimit) {
+		t.Logf("key longer than 8 should fail with ErrLimit, got %v", err)
+		t.Fail()
+	}
+	for _, k := range []string{"test", "team", "tester"} {
+		if _, err := r.TryInsert(k, k); err != nil {
+			t.Fatalf("%s should be inserted: %s", k, err)
+		}
+	}
+	// test, team, tester and the split node te
+	if r.tree.nodes != 4 || r.tree.keys != 3 {
+		t.Logf("tree should have 4 nodes and 3 keys, has %d and %d", r.tree.nodes, r.tree.keys)
+		t.Fail()
+	}
+	if _, err := r.TryInsert("tea", "a"); !errors.Is(err, ErrLimit) {
+		t.Logf("fourth key should fail with ErrLimit, got %v", err)
+		t.Fail()
+	}
+	if r.Insert("tea", "a") != nil || r.Len() != 3 {
+		t.Logf("Insert should not insert over the limit")
+		t.Fail()
+	}
+	// Overwriting a key is fine.
+	if _, err := r.TryInsert("test", "b"); err != nil {
+		t.Logf("overwriting test should be allowed: %s", err)
+		t.Fail()
+	}
+	r.Remove("tester")
+	if _, err := r.TryInsert("teamster", "a"); err != nil {
+		t.Logf("after Remove there should be room for another key: %s", err)
+		t.Fail()
+	}
+	keys, nodes := r.tree.keys, r.tree.nodes
+	r.recount()
+	if r.tree.keys != keys || r.tree.nodes != nodes {
+		t.Logf("keys and nodes should be counted correctly")
+		t.Fail()
+	}
+}
+
+func TestNewWithSize(t *testing.T) {
+	r := NewWithSize(16)
+	keys := []string{"test", "team", "tester", "toast", "slow", "slower", "water"}
+	for _, k := range keys {
+		r.Insert(k, k)
+	}
+	used := 16 - len(r.tree.arena)
+	r.settings() // counts the nodes
+	if used != r.tree.nodes {
+		t.Logf("%d nodes should be taken from the arena, %d are", r.tree.nodes, used)
+		t.Fail()
+	}
+	// More keys than preallocated nodes.
+	for _, k := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		r.Insert(k, k)
+	}
+	if len(r.tree.arena) != 0 || r.Len() != 15 {
+		t.Logf("tree should hold 15 keys and an empty arena, holds %d keys and %d nodes in the arena", r.Len(), len(r.tree.arena))
+		t.Fail()
+	}
+	for _, k := range append(keys, "a", "e") {
+		if n, exact := r.Find(k); !exact || n.Value != k {
+			t.Logf("%s should be found", k)
+			t.Fail()
+		}
+	}
+}
+
+func BenchmarkNewWithSize(b *testing.B) {
+	keys := make([]string, 100000)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i*7919) + "/key"
+	}
+	b.Run("New", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			r := New()
+			for _, k := range keys {
+				r.Insert(k, k)
+			}
+		}
+	})
+	b.Run("NewWithSize", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			r := NewWithSize(len(keys) * 3 / 2)
+			for _, k := range keys {
+				r.Insert(k, k)
+			}
+		}
+	})
+}
+
+func TestLeafChildren(t *testing.T) {
+	r := New()
+	for _, k := range []string{"test", "tester", "team", "toast", "slow"} {
+		r.Insert(k, k)
+	}
+	c := r.Clone()
+	r.Remove("tester") // leaves an empty map in test
+	for _, tree := range []*Radix{c, r.Clone(), r.Filter(func(string, interface{}) bool { return true })} {
+		tree.walk("", func(key string, n *Radix) {
+			if len(n.children) == 0 && n.children != nil {
+				t.Logf("leaf %q should not have a children map", key)
+				t.Fail()
+			}
+		})
+	}
+}
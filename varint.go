@@ -0,0 +1,66 @@
+package radix
+
+import (
+	"errors"
+	"io"
+)
+
+// The uvarint encoding of encoding/binary, which is not used because it pulls in reflect,
+// see the package documentation.
+
+const maxVarintLen64 = 10
+
+var errOverflow = errors.New("radix: varint overflows a 64-bit integer")
+
+// appendUvarint appends the uvarint encoding of x to b.
+func appendUvarint(b []byte, x uint64) []byte {
+	for x >= 0x80 {
+		b = append(b, byte(x)|0x80)
+		x >>= 7
+	}
+	return append(b, byte(x))
+}
+
+// uvarint decodes a uvarint from b and returns it with the number of bytes read. If there is
+// no valid uvarint at the start of b, n is 0 or negative, as with binary.Uvarint.
+func uvarint(b []byte) (x uint64, n int) {
+	var s uint
+	for i, c := range b {
+		if i == maxVarintLen64 {
+			return 0, -(i + 1)
+		}
+		if c < 0x80 {
+			if i == maxVarintLen64-1 && c > 1 {
+				return 0, -(i + 1)
+			}
+			return x | uint64(c)<<s, i + 1
+		}
+		x |= uint64(c&0x7f) << s
+		s += 7
+	}
+	return 0, 0
+}
+
+// readUvarint reads a uvarint from r.
+func readUvarint(r io.ByteReader) (uint64, error) {
+	var x uint64
+	var s uint
+	for i := 0; i < maxVarintLen64; i++ {
+		c, err := r.ReadByte()
+		if err != nil {
+			if i > 0 && err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return x, err
+		}
+		if c < 0x80 {
+			if i == maxVarintLen64-1 && c > 1 {
+				return x, errOverflow
+			}
+			return x | uint64(c)<<s, nil
+		}
+		x |= uint64(c&0x7f) << s
+		s += 7
+	}
+	return x, errOverflow
+}